@@ -0,0 +1,295 @@
+//||------------------------------------------------------------------------------------------------||
+//|| App Package: Application & Bootstrap
+//|| application.go
+//||------------------------------------------------------------------------------------------------||
+
+package app
+
+//||------------------------------------------------------------------------------------------------||
+//|| Import
+//||------------------------------------------------------------------------------------------------||
+
+import (
+	"errors"
+	"fmt"
+	nethttp "net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ralphferrara/aria/cache"
+	"github.com/ralphferrara/aria/config"
+	"github.com/ralphferrara/aria/db"
+	"github.com/ralphferrara/aria/http"
+	"github.com/ralphferrara/aria/locale"
+	"github.com/ralphferrara/aria/log"
+	"github.com/ralphferrara/aria/queue"
+	"github.com/ralphferrara/aria/storage"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Subsystems (used in InitError)
+//||------------------------------------------------------------------------------------------------||
+
+const (
+	SubsystemConfig  = "config"
+	SubsystemHTTP    = "http"
+	SubsystemStorage = "storage"
+	SubsystemDB      = "db"
+	SubsystemQueue   = "queue"
+	SubsystemLocale  = "locale"
+	SubsystemCache   = "cache"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Application: every subsystem built from one config
+//||------------------------------------------------------------------------------------------------||
+
+type Application struct {
+	Config    *config.Config
+	HTTP      map[string]*http.HTTPWrapper
+	Storages  map[string]*storage.Storage
+	Databases map[string]db.Database
+	SQLDB     map[string]*db.GormWrapper
+	MongoDB   map[string]*db.MongoWrapper
+	Queues    map[string]queue.Queue
+	Caches    map[string]cache.Cache
+	Log       log.Logger
+	Locales   locale.LocaleWrapper
+
+	hooksMu    sync.Mutex
+	startHooks []hook
+	stopHooks  []hook
+	checks     map[string]Check
+	draining   atomic.Bool
+
+	configMu sync.RWMutex   // guards Config once Reload/Watch may swap it
+	source   *config.Source // nil when built WithConfig (nothing to reload)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| InitError: names the subsystem (and instance, when known) that failed to start
+//||------------------------------------------------------------------------------------------------||
+
+type InitError struct {
+	Subsystem string
+	Instance  string
+	Err       error
+}
+
+func (e *InitError) Error() string {
+	if e.Instance != "" {
+		return fmt.Sprintf("init %s '%s': %v", e.Subsystem, e.Instance, e.Err)
+	}
+	return fmt.Sprintf("init %s: %v", e.Subsystem, e.Err)
+}
+
+func (e *InitError) Unwrap() error {
+	return e.Err
+}
+
+func initError(subsystem string, err error) *InitError {
+	out := &InitError{Subsystem: subsystem, Err: err}
+	var inst *config.InstanceError
+	if errors.As(err, &inst) {
+		out.Instance = inst.Name
+		out.Err = inst.Err
+	}
+	return out
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Options
+//||------------------------------------------------------------------------------------------------||
+
+type Option func(*options)
+
+type options struct {
+	load   config.LoadOptions
+	config *config.Config
+	source *config.Source
+	logger log.Logger
+	only   []string
+	lazy   bool
+}
+
+// WithConfigFile loads configuration from path (ignored when WithConfig is set).
+func WithConfigFile(path string) Option {
+	return func(o *options) { o.load.Path = path }
+}
+
+// WithLoadOptions loads configuration through every layer in opts (env overlay, ARIA_ vars, -set flags).
+func WithLoadOptions(opts config.LoadOptions) Option {
+	return func(o *options) { o.load = opts }
+}
+
+// WithConfig uses an already-loaded configuration.
+func WithConfig(cfg *config.Config) Option {
+	return func(o *options) { o.config = cfg }
+}
+
+// withSource binds the application to an already-loaded source (Init: the config singleton).
+func withSource(src *config.Source) Option {
+	return func(o *options) { o.source = src }
+}
+
+// WithLogger replaces the default "aria" logger.
+func WithLogger(l log.Logger) Option {
+	return func(o *options) { o.logger = l }
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| New: build every subsystem; on failure, release what was built and return *InitError
+//||
+//|| Each call loads its own config (config.Load), so several applications can run side by side;
+//|| only the deprecated Init publishes it as the config package singleton. Use a.Reload/a.Watch
+//|| (not config.Reload/Watch) to reload an application's config.
+//||------------------------------------------------------------------------------------------------||
+
+func New(opts ...Option) (*Application, error) {
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Options + Logger
+	//||------------------------------------------------------------------------------------------------||
+
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	a := &Application{Log: o.logger}
+	if a.Log == nil {
+		a.Log = log.Init("aria")
+	}
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Config
+	//||------------------------------------------------------------------------------------------------||
+
+	a.Config, a.source = o.config, o.source
+	switch {
+	case a.source != nil:
+		if a.Config = a.source.Config(); a.Config == nil {
+			return nil, initError(SubsystemConfig, fmt.Errorf("config not loaded"))
+		}
+	case a.Config == nil:
+		cfg, err := config.Load(o.load)
+		if err != nil {
+			return nil, initError(SubsystemConfig, err)
+		}
+		if cfg.App.Debug {
+			config.PrintConsoleConfig(cfg) // merged layers, secrets redacted
+		}
+		a.Config = cfg
+		a.source = config.NewSource(o.load, cfg)
+	}
+	if a.source != nil {
+		a.source.OnChange("*", func(_, next *config.Config) { a.swapConfig(next) })
+	}
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Selection + Lazy (subsystems are built from a filtered copy; a.Config stays complete)
+	//||------------------------------------------------------------------------------------------------||
+
+	sel, err := parseSelectors(o.only)
+	if err != nil {
+		return nil, initError(SubsystemConfig, err)
+	}
+	cfg, err := selectConfig(a.Config, sel, o.lazy)
+	if err != nil {
+		return nil, initError(SubsystemConfig, err)
+	}
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Constants (package-level)
+	//||------------------------------------------------------------------------------------------------||
+
+	InitConstants()
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| HTTP(s)
+	//||------------------------------------------------------------------------------------------------||
+
+	if a.HTTP, err = http.Init(cfg); err != nil {
+		return nil, a.fail(SubsystemHTTP, err)
+	}
+	for _, h := range a.HTTP {
+		h.HTTPConfig.Middleware = a.withMiddleware(h.HTTPConfig.Middleware)
+	}
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Storages
+	//||------------------------------------------------------------------------------------------------||
+
+	if a.Storages, err = storage.Init(cfg); err != nil {
+		return nil, a.fail(SubsystemStorage, err)
+	}
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Databases
+	//||------------------------------------------------------------------------------------------------||
+
+	if a.Databases, err = db.Init(cfg); err != nil {
+		return nil, a.fail(SubsystemDB, err)
+	}
+	a.SQLDB, a.MongoDB = db.Split(a.Databases)
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Queues
+	//||------------------------------------------------------------------------------------------------||
+
+	if a.Queues, err = queue.Init(cfg); err != nil {
+		return nil, a.fail(SubsystemQueue, err)
+	}
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Locales
+	//||------------------------------------------------------------------------------------------------||
+
+	if sel.enabled(SubsystemLocale) {
+		if a.Locales, err = locale.Init(cfg.Locale.Directory); err != nil {
+			return nil, a.fail(SubsystemLocale, err)
+		}
+	}
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Caches
+	//||------------------------------------------------------------------------------------------------||
+
+	if a.Caches, err = cache.Init(cfg); err != nil {
+		return nil, a.fail(SubsystemCache, err)
+	}
+
+	return a, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| withMiddleware: run the configured middleware with a already in the request context
+//||------------------------------------------------------------------------------------------------||
+
+func (a *Application) withMiddleware(inner func(nethttp.Handler) nethttp.Handler) func(nethttp.Handler) nethttp.Handler {
+	return func(next nethttp.Handler) nethttp.Handler {
+		if inner != nil {
+			next = inner(next)
+		}
+		return a.Middleware(next)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| fail: close whatever New already opened, then build the InitError
+//||------------------------------------------------------------------------------------------------||
+
+func (a *Application) fail(subsystem string, err error) error {
+	for _, c := range a.Caches {
+		_ = closeIf(c)
+	}
+	for _, q := range a.Queues {
+		_ = closeIf(q)
+	}
+	for _, d := range a.Databases {
+		_ = closeIf(d)
+	}
+	for _, s := range a.Storages {
+		_ = closeIf(s)
+	}
+	return initError(subsystem, err)
+}
@@ -0,0 +1,63 @@
+//||------------------------------------------------------------------------------------------------||
+//|| App Package: Error & Constant Catalog Export
+//|| catalog.go
+//||------------------------------------------------------------------------------------------------||
+
+package app
+
+//||------------------------------------------------------------------------------------------------||
+//|| Import
+//||------------------------------------------------------------------------------------------------||
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Catalog: every error and constant library (frontends sync codes from this)
+//||
+//|| Error keys are what ErrorsLibrary.Code/Error return: errors.AUTH.TF_CODE_MISMATCH -> "AUTH.TF_CODE_MISMATCH"
+//||------------------------------------------------------------------------------------------------||
+
+type Catalog struct {
+	Errors    map[string]map[string]ErrorsEntry    `json:"errors"`
+	Constants map[string]map[string]ConstantsEntry `json:"constants"`
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| ExportCatalog / CatalogJSON
+//||------------------------------------------------------------------------------------------------||
+
+func ExportCatalog() Catalog {
+	return Catalog{
+		Errors:    ErrorsStore.Export(),
+		Constants: ConstantsStore.Export(),
+	}
+}
+
+// CatalogJSON encodes ExportCatalog; map keys are sorted, so output is stable between builds.
+func CatalogJSON() ([]byte, error) {
+	return json.Marshal(ExportCatalog())
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| CatalogHandler: GET returns the catalog as JSON
+//||------------------------------------------------------------------------------------------------||
+
+func CatalogHandler() nethttp.Handler {
+	return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if r.Method != nethttp.MethodGet && r.Method != nethttp.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			nethttp.Error(w, "method not allowed", nethttp.StatusMethodNotAllowed)
+			return
+		}
+		body, err := CatalogJSON()
+		if err != nil {
+			nethttp.Error(w, "catalog export failed", nethttp.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	})
+}
@@ -1,12 +1,17 @@
 package app
 
-import "github.com/ralphferrara/aria/locale"
+import (
+	"sync"
+
+	"github.com/ralphferrara/aria/locale"
+)
 
 //||------------------------------------------------------------------------------------------------||
 //|| Store
 //||------------------------------------------------------------------------------------------------||
 
 var ConstantsStore = ConstantsMaster{
+	mu:      &sync.RWMutex{},
 	entries: make(map[string]ConstantsLibrary),
 }
 
@@ -15,6 +20,7 @@ var ConstantsStore = ConstantsMaster{
 //||------------------------------------------------------------------------------------------------||
 
 type ConstantsMaster struct {
+	mu      *sync.RWMutex // shared with every library; init() writers race runtime readers
 	entries map[string]ConstantsLibrary
 }
 
@@ -23,6 +29,7 @@ type ConstantsMaster struct {
 //||------------------------------------------------------------------------------------------------||
 
 type ConstantsLibrary struct {
+	mu      *sync.RWMutex
 	entries map[string]ConstantsEntry
 }
 
@@ -44,9 +51,6 @@ type ConstantsEntry struct {
 //||------------------------------------------------------------------------------------------------||
 
 func Constants(libraryName string) ConstantsLibrary {
-	if !ConstantsStore.HasLibrary(libraryName) {
-		ConstantsStore.AddLibrary(libraryName)
-	}
 	return ConstantsStore.GetLibrary(libraryName)
 }
 
@@ -55,6 +59,8 @@ func Constants(libraryName string) ConstantsLibrary {
 //||------------------------------------------------------------------------------------------------||
 
 func (lib ConstantsLibrary) Get(name string) ConstantsEntry {
+	lib.mu.RLock()
+	defer lib.mu.RUnlock()
 	name = formatName(name)
 	if entry, exists := lib.entries[name]; exists {
 		return entry
@@ -68,6 +74,8 @@ func (lib ConstantsLibrary) Get(name string) ConstantsEntry {
 }
 
 func (lib ConstantsLibrary) Bool(name string) bool {
+	lib.mu.RLock()
+	defer lib.mu.RUnlock()
 	name = formatName(name)
 	if entry, exists := lib.entries[name]; exists {
 		return entry.ValueBool
@@ -76,6 +84,8 @@ func (lib ConstantsLibrary) Bool(name string) bool {
 }
 
 func (lib ConstantsLibrary) Int(name string) int {
+	lib.mu.RLock()
+	defer lib.mu.RUnlock()
 	name = formatName(name)
 	if entry, exists := lib.entries[name]; exists {
 		return entry.ValueInt
@@ -84,6 +94,8 @@ func (lib ConstantsLibrary) Int(name string) int {
 }
 
 func (lib ConstantsLibrary) String(name string) string {
+	lib.mu.RLock()
+	defer lib.mu.RUnlock()
 	name = formatName(name)
 	if entry, exists := lib.entries[name]; exists {
 		return entry.ValueString
@@ -92,6 +104,8 @@ func (lib ConstantsLibrary) String(name string) string {
 }
 
 func (lib ConstantsLibrary) Locale(code string, lang string) string {
+	lib.mu.RLock()
+	defer lib.mu.RUnlock()
 	code = formatName(code)
 	if entry, exists := lib.entries[code]; exists {
 		translate, err := locale.GetTranslation("constants", entry.Code, lang)
@@ -103,6 +117,8 @@ func (lib ConstantsLibrary) Locale(code string, lang string) string {
 }
 
 func (lib ConstantsLibrary) Code(name string) string {
+	lib.mu.RLock()
+	defer lib.mu.RUnlock()
 	name = formatName(name)
 	if entry, exists := lib.entries[name]; exists {
 		return entry.Code
@@ -116,6 +132,8 @@ func (lib ConstantsLibrary) Desciption(name string) string {
 }
 
 func (lib ConstantsLibrary) List() []ConstantsEntry {
+	lib.mu.RLock()
+	defer lib.mu.RUnlock()
 	list := []ConstantsEntry{}
 	for _, entry := range lib.entries {
 		list = append(list, entry)
@@ -129,6 +147,8 @@ func (lib ConstantsLibrary) List() []ConstantsEntry {
 
 func (lib ConstantsLibrary) AddString(name, value string) {
 	name = formatName(name)
+	lib.mu.Lock()
+	defer lib.mu.Unlock()
 	lib.entries[name] = ConstantsEntry{
 		Type:        "string",
 		Name:        name,
@@ -138,6 +158,8 @@ func (lib ConstantsLibrary) AddString(name, value string) {
 
 func (lib ConstantsLibrary) AddCode(name, code, description string) {
 	name = formatName(name)
+	lib.mu.Lock()
+	defer lib.mu.Unlock()
 	lib.entries[name] = ConstantsEntry{
 		Type:        "string",
 		Name:        name,
@@ -148,6 +170,8 @@ func (lib ConstantsLibrary) AddCode(name, code, description string) {
 
 func (lib ConstantsLibrary) AddInt(name string, value int) {
 	name = formatName(name)
+	lib.mu.Lock()
+	defer lib.mu.Unlock()
 	lib.entries[name] = ConstantsEntry{
 		Type:     "int",
 		Name:     name,
@@ -157,6 +181,8 @@ func (lib ConstantsLibrary) AddInt(name string, value int) {
 
 func (lib ConstantsLibrary) AddBool(name string, value bool) {
 	name = formatName(name)
+	lib.mu.Lock()
+	defer lib.mu.Unlock()
 	lib.entries[name] = ConstantsEntry{
 		Type:      "bool",
 		Name:      name,
@@ -169,22 +195,50 @@ func (lib ConstantsLibrary) AddBool(name string, value bool) {
 //||------------------------------------------------------------------------------------------------||
 
 func (m ConstantsMaster) AddLibrary(name string) {
-	name = formatName(name)
-	if _, exists := m.entries[name]; !exists {
-		m.entries[name] = ConstantsLibrary{entries: make(map[string]ConstantsEntry)}
-	}
+	m.GetLibrary(name)
 }
 
 func (m ConstantsMaster) HasLibrary(name string) bool {
 	name = formatName(name)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	_, ok := m.entries[name]
 	return ok
 }
 
+// GetLibrary runs on every Constants() lookup, so existing libraries only take the read lock.
 func (m ConstantsMaster) GetLibrary(name string) ConstantsLibrary {
 	name = formatName(name)
-	if _, exists := m.entries[name]; !exists {
-		m.entries[name] = ConstantsLibrary{entries: make(map[string]ConstantsEntry)}
+	m.mu.RLock()
+	lib, exists := m.entries[name]
+	m.mu.RUnlock()
+	if exists {
+		return lib
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if lib, exists := m.entries[name]; exists {
+		return lib
+	}
+	lib = ConstantsLibrary{mu: m.mu, entries: make(map[string]ConstantsEntry)}
+	m.entries[name] = lib
+	return lib
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Export: snapshot of every library, keyed LIBRARY -> NAME
+//||------------------------------------------------------------------------------------------------||
+
+func (m ConstantsMaster) Export() map[string]map[string]ConstantsEntry {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make(map[string]map[string]ConstantsEntry, len(m.entries))
+	for libName, lib := range m.entries {
+		entries := make(map[string]ConstantsEntry, len(lib.entries))
+		for name, entry := range lib.entries {
+			entries[name] = entry
+		}
+		out[libName] = entries
 	}
-	return m.entries[name]
+	return out
 }
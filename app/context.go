@@ -0,0 +1,113 @@
+//||------------------------------------------------------------------------------------------------||
+//|| App Package: Default Instance & Context
+//|| context.go
+//||------------------------------------------------------------------------------------------------||
+
+package app
+
+//||------------------------------------------------------------------------------------------------||
+//|| Import
+//||------------------------------------------------------------------------------------------------||
+
+import (
+	"context"
+	nethttp "net/http"
+	"sync"
+
+	"github.com/ralphferrara/aria/log"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Default Instance (backs the deprecated package-level globals)
+//||------------------------------------------------------------------------------------------------||
+
+var (
+	defaultMu  sync.RWMutex
+	defaultApp *Application
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| SetDefault: make a the default instance and mirror it into the legacy globals
+//||------------------------------------------------------------------------------------------------||
+
+func SetDefault(a *Application) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultApp = a
+	Config = a.Config
+	HTTP = a.HTTP
+	Storages = a.Storages
+	Databases = a.Databases
+	SQLDB = a.SQLDB
+	MongoDB = a.MongoDB
+	Queues = a.Queues
+	Caches = a.Caches
+	Log = a.Log
+	Locales = a.Locales
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Default: the instance set by Init/SetDefault, else one built once from the legacy globals
+//||------------------------------------------------------------------------------------------------||
+
+func Default() *Application {
+	defaultMu.RLock()
+	a := defaultApp
+	defaultMu.RUnlock()
+	if a != nil {
+		return a
+	}
+
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultApp != nil {
+		return defaultApp
+	}
+	defaultApp = &Application{
+		Config:    Config,
+		HTTP:      HTTP,
+		Storages:  Storages,
+		Databases: Databases,
+		SQLDB:     SQLDB,
+		MongoDB:   MongoDB,
+		Queues:    Queues,
+		Caches:    Caches,
+		Log:       Log,
+		Locales:   Locales,
+	}
+	if defaultApp.Log == nil {
+		defaultApp.Log = log.DefaultLogger{Module: "aria"}
+	}
+	return defaultApp
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Context
+//||------------------------------------------------------------------------------------------------||
+
+type contextKey struct{}
+
+// WithContext returns a copy of ctx carrying a.
+func WithContext(ctx context.Context, a *Application) context.Context {
+	return context.WithValue(ctx, contextKey{}, a)
+}
+
+// FromContext returns the Application carried by ctx, or Default() when there is none.
+func FromContext(ctx context.Context) *Application {
+	if ctx != nil {
+		if a, ok := ctx.Value(contextKey{}).(*Application); ok && a != nil {
+			return a
+		}
+	}
+	return Default()
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Middleware: attach a to every request context
+//||------------------------------------------------------------------------------------------------||
+
+func (a *Application) Middleware(next nethttp.Handler) nethttp.Handler {
+	return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		next.ServeHTTP(w, r.WithContext(WithContext(r.Context(), a)))
+	})
+}
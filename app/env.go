@@ -10,5 +10,10 @@ package app
 //||------------------------------------------------------------------------------------------------||
 
 func InProduction() bool {
-	return Config.App.Env == "production"
+	return Default().InProduction()
+}
+
+func (a *Application) InProduction() bool {
+	cfg := a.CurrentConfig()
+	return cfg != nil && cfg.App.Env == "production"
 }
@@ -0,0 +1,86 @@
+//||------------------------------------------------------------------------------------------------||
+//|| App Package: Typed Application Error
+//|| error.go
+//||------------------------------------------------------------------------------------------------||
+
+package app
+
+//||------------------------------------------------------------------------------------------------||
+//|| Import
+//||------------------------------------------------------------------------------------------------||
+
+import (
+	"fmt"
+	"maps"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Error: a registered error code plus the context it was raised with
+//||------------------------------------------------------------------------------------------------||
+
+type Error struct {
+	Library string         `json:"library"`
+	Code    string         `json:"code"`
+	Message string         `json:"message"`
+	Status  int            `json:"status"`
+	Fatal   bool           `json:"fatal"`
+	Cause   error          `json:"-"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| error interface: "AUTH.CODE" (unchanged from the old string errors)
+//||------------------------------------------------------------------------------------------------||
+
+func (e *Error) Error() string {
+	return e.Key()
+}
+
+func (e *Error) Key() string {
+	return fmt.Sprintf("%s.%s", e.Library, e.Code)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// Is matches any *Error with the same library and code, so
+// errors.Is(err, app.Err("Auth").Error("ACCOUNT_MISMATCH")) works regardless of cause/details.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && e.Library == t.Library && e.Code == t.Code
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Wrap / WithDetail: return copies so the registered entry is never mutated
+//||------------------------------------------------------------------------------------------------||
+
+func (e *Error) Wrap(cause error) *Error {
+	out := *e
+	out.Cause = cause
+	return &out
+}
+
+func (e *Error) WithDetail(field string, value any) *Error {
+	out := *e
+	out.Details = maps.Clone(e.Details)
+	if out.Details == nil {
+		out.Details = map[string]any{}
+	}
+	out.Details[field] = value
+	return &out
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Localize: translated message for lang, falling back to the registered message
+//||------------------------------------------------------------------------------------------------||
+
+func (e *Error) Localize(lang string) string {
+	if !ErrorsStore.HasLibrary(e.Library) {
+		return e.Message
+	}
+	if translate, ok := Err(e.Library).translate(e.Code, lang); ok {
+		return translate
+	}
+	return e.Message
+}
@@ -1,9 +1,10 @@
 package app
 
 import (
-	"errors"
 	"fmt"
+	nethttp "net/http"
 	"strings"
+	"sync"
 
 	"github.com/ralphferrara/aria/locale"
 )
@@ -25,6 +26,7 @@ func formatName(name string) string {
 //||------------------------------------------------------------------------------------------------||
 
 var ErrorsStore = ErrorsMaster{
+	mu:      &sync.RWMutex{},
 	entries: make(map[string]ErrorsLibrary),
 }
 
@@ -33,6 +35,7 @@ var ErrorsStore = ErrorsMaster{
 //||------------------------------------------------------------------------------------------------||
 
 type ErrorsMaster struct {
+	mu      *sync.RWMutex // shared with every library; init() writers race runtime readers
 	entries map[string]ErrorsLibrary
 }
 
@@ -42,6 +45,7 @@ type ErrorsMaster struct {
 
 type ErrorsLibrary struct {
 	name    string
+	mu      *sync.RWMutex
 	entries map[string]ErrorsEntry
 }
 
@@ -53,6 +57,7 @@ type ErrorsEntry struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
 	Fatal   bool   `json:"fatal"`
+	Status  int    `json:"status"`
 }
 
 //||------------------------------------------------------------------------------------------------||
@@ -60,10 +65,6 @@ type ErrorsEntry struct {
 //||------------------------------------------------------------------------------------------------||
 
 func Err(libraryName string) ErrorsLibrary {
-	libraryName = formatName(libraryName)
-	if !ErrorsStore.HasLibrary(libraryName) {
-		ErrorsStore.AddLibrary(libraryName)
-	}
 	return ErrorsStore.GetLibrary(libraryName)
 }
 
@@ -103,6 +104,8 @@ func ErrFatal(code string) bool {
 
 func (lib ErrorsLibrary) Get(code string) ErrorsEntry {
 	code = formatName(code)
+	lib.mu.RLock()
+	defer lib.mu.RUnlock()
 	if entry, exists := lib.entries[code]; exists {
 		return entry
 	}
@@ -116,6 +119,8 @@ func (lib ErrorsLibrary) Get(code string) ErrorsEntry {
 
 func (lib ErrorsLibrary) Code(code string) string {
 	code = formatName(code)
+	lib.mu.RLock()
+	defer lib.mu.RUnlock()
 	fmt.Println("Error:", code)
 	if entry, exists := lib.entries[code]; exists {
 		return fmt.Sprintf("%s.%s", strings.ToUpper(lib.name), entry.Code)
@@ -126,6 +131,8 @@ func (lib ErrorsLibrary) Code(code string) string {
 
 func (lib ErrorsLibrary) Message(code string) string {
 	code = formatName(code)
+	lib.mu.RLock()
+	defer lib.mu.RUnlock()
 	if entry, exists := lib.entries[code]; exists {
 		return entry.Message
 
@@ -135,27 +142,59 @@ func (lib ErrorsLibrary) Message(code string) string {
 }
 
 func (lib ErrorsLibrary) Locale(code string, lang string) string {
+	if translate, ok := lib.translate(code, lang); ok {
+		return translate
+	}
+	return "UNDEFINED LOCALE ERROR MESSAGE"
+}
+
+// translate tries lang as given ("en-US"), then its primary subtag ("en").
+func (lib ErrorsLibrary) translate(code string, lang string) (string, bool) {
 	code = formatName(code)
-	if entry, exists := lib.entries[code]; exists {
-		translate, err := locale.GetTranslation("errors", entry.Code, lang)
-		if err == nil {
-			return translate
+	lib.mu.RLock()
+	entry, exists := lib.entries[code]
+	lib.mu.RUnlock()
+	if !exists {
+		return "", false
+	}
+	for _, l := range []string{lang, strings.SplitN(lang, "-", 2)[0]} {
+		if translate, err := locale.GetTranslation("errors", entry.Code, l); err == nil {
+			return translate, true
 		}
 	}
-	return "UNDEFINED LOCALE ERROR MESSAGE"
+	return "", false
 }
 
 func (lib ErrorsLibrary) Error(code string) error {
+	return lib.New(code)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| New: typed *Error for code (ARIA.UNDEFINED_ERROR when the code is not registered)
+//||------------------------------------------------------------------------------------------------||
+
+func (lib ErrorsLibrary) New(code string) *Error {
 	code = formatName(code)
-	if entry, exists := lib.entries[code]; exists {
-		return fmt.Errorf("%s.%s", strings.ToUpper(lib.name), entry.Code)
+	lib.mu.RLock()
+	entry, exists := lib.entries[code]
+	lib.mu.RUnlock()
+	if !exists {
+		fmt.Println("UNDEFINED ERROR CODE:", lib.name, code)
+		return &Error{Library: "ARIA", Code: "UNDEFINED_ERROR", Message: "UNDEFINED ERROR MESSAGE", Status: nethttp.StatusInternalServerError}
+	}
+	return &Error{
+		Library: strings.ToUpper(lib.name),
+		Code:    entry.Code,
+		Message: entry.Message,
+		Status:  entry.Status,
+		Fatal:   entry.Fatal,
 	}
-	fmt.Println("UNDEFINED ERROR CODE:", lib.name, code)
-	return errors.New("UNDEFINED_ERROR")
 }
 
 func (lib ErrorsLibrary) IsFatal(code string) bool {
 	code = formatName(code)
+	lib.mu.RLock()
+	defer lib.mu.RUnlock()
 	if entry, exists := lib.entries[code]; exists {
 		return entry.Fatal
 	}
@@ -163,6 +202,8 @@ func (lib ErrorsLibrary) IsFatal(code string) bool {
 }
 
 func (lib ErrorsLibrary) List() []ErrorsEntry {
+	lib.mu.RLock()
+	defer lib.mu.RUnlock()
 	list := []ErrorsEntry{}
 	for _, entry := range lib.entries {
 		list = append(list, entry)
@@ -174,12 +215,24 @@ func (lib ErrorsLibrary) List() []ErrorsEntry {
 //|| Var
 //||------------------------------------------------------------------------------------------------||
 
-func (lib ErrorsLibrary) Add(code, message string, fatal bool) {
+// Add registers code. status is the HTTP status to answer with; without one, fatal
+// errors default to 500 and everything else to 400.
+func (lib ErrorsLibrary) Add(code, message string, fatal bool, status ...int) {
 	code = formatName(code)
+	httpStatus := nethttp.StatusBadRequest
+	if fatal {
+		httpStatus = nethttp.StatusInternalServerError
+	}
+	if len(status) > 0 && status[0] != 0 {
+		httpStatus = status[0]
+	}
+	lib.mu.Lock()
+	defer lib.mu.Unlock()
 	lib.entries[code] = ErrorsEntry{
 		Code:    code,
 		Message: message,
 		Fatal:   fatal,
+		Status:  httpStatus,
 	}
 }
 
@@ -188,22 +241,50 @@ func (lib ErrorsLibrary) Add(code, message string, fatal bool) {
 //||------------------------------------------------------------------------------------------------||
 
 func (m ErrorsMaster) AddLibrary(libName string) {
-	libName = formatName(libName)
-	if _, exists := m.entries[libName]; !exists {
-		m.entries[libName] = ErrorsLibrary{name: libName, entries: make(map[string]ErrorsEntry)}
-	}
+	m.GetLibrary(libName)
 }
 
 func (m ErrorsMaster) HasLibrary(code string) bool {
 	code = formatName(code)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	_, ok := m.entries[code]
 	return ok
 }
 
+// GetLibrary runs on every Err() lookup, so existing libraries only take the read lock.
 func (m ErrorsMaster) GetLibrary(libName string) ErrorsLibrary {
 	libName = formatName(libName)
-	if _, exists := m.entries[libName]; !exists {
-		m.entries[libName] = ErrorsLibrary{name: libName, entries: make(map[string]ErrorsEntry)}
+	m.mu.RLock()
+	lib, exists := m.entries[libName]
+	m.mu.RUnlock()
+	if exists {
+		return lib
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if lib, exists := m.entries[libName]; exists {
+		return lib
+	}
+	lib = ErrorsLibrary{name: libName, mu: m.mu, entries: make(map[string]ErrorsEntry)}
+	m.entries[libName] = lib
+	return lib
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Export: snapshot of every library, keyed LIBRARY -> CODE
+//||------------------------------------------------------------------------------------------------||
+
+func (m ErrorsMaster) Export() map[string]map[string]ErrorsEntry {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make(map[string]map[string]ErrorsEntry, len(m.entries))
+	for libName, lib := range m.entries {
+		entries := make(map[string]ErrorsEntry, len(lib.entries))
+		for code, entry := range lib.entries {
+			entries[code] = entry
+		}
+		out[libName] = entries
 	}
-	return m.entries[libName]
+	return out
 }
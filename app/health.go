@@ -0,0 +1,181 @@
+//||------------------------------------------------------------------------------------------------||
+//|| App Package: Liveness & Readiness
+//|| health.go
+//||------------------------------------------------------------------------------------------------||
+
+package app
+
+//||------------------------------------------------------------------------------------------------||
+//|| Import
+//||------------------------------------------------------------------------------------------------||
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+	"sync"
+	"time"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Defaults
+//||------------------------------------------------------------------------------------------------||
+
+const (
+	LivenessPath        = "/healthz"
+	ReadinessPath       = "/readyz"
+	defaultCheckTimeout = 2 * time.Second
+)
+
+const (
+	StatusOK       = "ok"
+	StatusFail     = "fail"
+	StatusDraining = "draining"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Check: one readiness probe (subsystem pings are registered automatically)
+//||------------------------------------------------------------------------------------------------||
+
+type Check func(ctx context.Context) error
+
+type ComponentStatus struct {
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+	LatencyMS int64  `json:"latency_ms"`
+}
+
+type Readiness struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentStatus `json:"components"`
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| AddCheck: add (or replace) a custom readiness check
+//||------------------------------------------------------------------------------------------------||
+
+func (a *Application) AddCheck(name string, fn Check) {
+	a.hooksMu.Lock()
+	defer a.hooksMu.Unlock()
+	if a.checks == nil {
+		a.checks = map[string]Check{}
+	}
+	a.checks[name] = fn
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Ready: run every check in parallel, each bounded by timeout (<= 0 uses the default)
+//||------------------------------------------------------------------------------------------------||
+
+func (a *Application) Ready(ctx context.Context, timeout time.Duration) Readiness {
+	if timeout <= 0 {
+		timeout = defaultCheckTimeout
+	}
+	checks := a.readinessChecks()
+	report := Readiness{Status: StatusOK, Components: make(map[string]ComponentStatus, len(checks))}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for name, fn := range checks {
+		wg.Add(1)
+		go func(name string, fn Check) {
+			defer wg.Done()
+			cctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			started := time.Now()
+			err := runHook(cctx, hook{name: name, fn: Hook(fn)})
+			status := ComponentStatus{Status: StatusOK, LatencyMS: time.Since(started).Milliseconds()}
+			if err != nil {
+				status.Status, status.Error = StatusFail, err.Error()
+			}
+
+			mu.Lock()
+			report.Components[name] = status
+			if err != nil {
+				report.Status = StatusFail
+			}
+			mu.Unlock()
+		}(name, fn)
+	}
+	wg.Wait()
+
+	if a.draining.Load() {
+		report.Status = StatusDraining
+	}
+	return report
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| readinessChecks: subsystem pings + custom checks
+//||------------------------------------------------------------------------------------------------||
+
+func (a *Application) readinessChecks() map[string]Check {
+	checks := map[string]Check{}
+	for name, s := range a.Storages {
+		checks[SubsystemStorage+":"+name] = s.PingContext
+	}
+	for name, d := range a.Databases {
+		checks[SubsystemDB+":"+name] = d.Ping
+	}
+	for name, c := range a.Caches {
+		checks[SubsystemCache+":"+name] = c.PingContext
+	}
+	for name, q := range a.Queues {
+		checks[SubsystemQueue+":"+name] = q.PingContext
+	}
+
+	a.hooksMu.Lock()
+	for name, fn := range a.checks {
+		checks[name] = fn
+	}
+	a.hooksMu.Unlock()
+	return checks
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Handlers
+//||------------------------------------------------------------------------------------------------||
+
+// LivenessHandler answers 200 while the process can serve HTTP; it never touches dependencies.
+func (a *Application) LivenessHandler() nethttp.Handler {
+	return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		writeHealth(w, nethttp.StatusOK, map[string]string{"status": StatusOK})
+	})
+}
+
+// ReadinessHandler answers 200 when every check passes, else 503, with per-component JSON.
+func (a *Application) ReadinessHandler(timeout time.Duration) nethttp.Handler {
+	return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		report := a.Ready(r.Context(), timeout)
+		status := nethttp.StatusOK
+		if report.Status != StatusOK {
+			status = nethttp.StatusServiceUnavailable
+		}
+		writeHealth(w, status, report)
+	})
+}
+
+func writeHealth(w nethttp.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| MountHealth: register /healthz and /readyz on the named HTTP server
+//||------------------------------------------------------------------------------------------------||
+
+func (a *Application) MountHealth(httpName string, timeout time.Duration) error {
+	h, ok := a.HTTP[httpName]
+	if !ok || h == nil {
+		return fmt.Errorf("http server '%s' not configured", httpName)
+	}
+	h.Handle(LivenessPath, a.LivenessHandler())
+	h.Handle(ReadinessPath, a.ReadinessHandler(timeout))
+	return nil
+}
@@ -0,0 +1,198 @@
+//||------------------------------------------------------------------------------------------------||
+//|| App Package: Lifecycle Hooks & Ordered Shutdown
+//|| lifecycle.go
+//||------------------------------------------------------------------------------------------------||
+
+package app
+
+//||------------------------------------------------------------------------------------------------||
+//|| Import
+//||------------------------------------------------------------------------------------------------||
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Priorities: OnStart runs ascending, OnStop runs descending
+//||
+//|| Start: storage -> db -> cache -> queue -> your hooks -> HTTP serve
+//|| Stop:  HTTP drain -> your hooks -> queue -> cache -> db -> storage
+//||------------------------------------------------------------------------------------------------||
+
+const (
+	PriorityStorage = 100
+	PriorityDB      = 200
+	PriorityCache   = 300
+	PriorityQueue   = 400
+	PriorityDefault = 500
+	PriorityHTTP    = 1000
+)
+
+const defaultShutdownTimeout = 5 * time.Second
+
+//||------------------------------------------------------------------------------------------------||
+//|| Hook
+//||------------------------------------------------------------------------------------------------||
+
+type Hook func(ctx context.Context) error
+
+type hook struct {
+	name     string
+	priority int
+	fn       Hook
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| OnStart / OnStop: register a named hook
+//||------------------------------------------------------------------------------------------------||
+
+func (a *Application) OnStart(name string, priority int, fn Hook) {
+	a.hooksMu.Lock()
+	defer a.hooksMu.Unlock()
+	a.startHooks = append(a.startHooks, hook{name: name, priority: priority, fn: fn})
+}
+
+func (a *Application) OnStop(name string, priority int, fn Hook) {
+	a.hooksMu.Lock()
+	defer a.hooksMu.Unlock()
+	a.stopHooks = append(a.stopHooks, hook{name: name, priority: priority, fn: fn})
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Start: run OnStart hooks, then start every HTTP server; stops at the first failure
+//||------------------------------------------------------------------------------------------------||
+
+func (a *Application) Start(ctx context.Context) error {
+	a.hooksMu.Lock()
+	hooks := append([]hook(nil), a.startHooks...)
+	a.hooksMu.Unlock()
+
+	for name, h := range a.HTTP {
+		h := h
+		hooks = append(hooks, hook{name: "http:" + name, priority: PriorityHTTP, fn: func(context.Context) error {
+			return h.Start()
+		}})
+	}
+	sort.SliceStable(hooks, func(i, j int) bool { return hooks[i].priority < hooks[j].priority })
+
+	for _, h := range hooks {
+		if err := runHook(ctx, h); err != nil {
+			return fmt.Errorf("start %s: %w", h.name, err)
+		}
+	}
+	return nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Shutdown: run every stop hook (built-in + OnStop) under ctx's deadline and join the errors
+//||------------------------------------------------------------------------------------------------||
+
+func (a *Application) Shutdown(ctx context.Context) error {
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Setup Deadline
+	//||------------------------------------------------------------------------------------------------||
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultShutdownTimeout)
+		defer cancel()
+	}
+	a.draining.Store(true) // readiness fails from here so load balancers stop routing
+	deadline, _ := ctx.Deadline()
+	a.Log.Info("Shutdown initiated (deadline: %s)", deadline.Format(time.RFC3339))
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Collect + Order (stable, so equal priorities keep registration order)
+	//||------------------------------------------------------------------------------------------------||
+
+	a.hooksMu.Lock()
+	hooks := append(a.builtinStopHooks(), a.stopHooks...)
+	a.hooksMu.Unlock()
+	sort.SliceStable(hooks, func(i, j int) bool { return hooks[i].priority > hooks[j].priority })
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Run (every hook runs even after failures; each is bounded by the deadline)
+	//||------------------------------------------------------------------------------------------------||
+
+	var errs []error
+	for _, h := range hooks {
+		if err := runHook(ctx, h); err != nil {
+			a.Log.Error("Stop '%s' failed: %v", h.name, err)
+			errs = append(errs, fmt.Errorf("stop %s: %w", h.name, err))
+			continue
+		}
+		a.Log.Info("Stopped '%s'", h.name)
+	}
+
+	a.Log.Info("Shutdown complete")
+	return errors.Join(errs...)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| builtinStopHooks: close every subsystem instance at its priority
+//||------------------------------------------------------------------------------------------------||
+
+func (a *Application) builtinStopHooks() []hook {
+	var hooks []hook
+	for name, h := range a.HTTP {
+		h := h
+		hooks = append(hooks, hook{name: "http:" + name, priority: PriorityHTTP, fn: func(ctx context.Context) error {
+			if h.Server == nil {
+				return nil
+			}
+			return h.Server.Shutdown(ctx)
+		}})
+	}
+	for name, q := range a.Queues {
+		hooks = append(hooks, closeHook("queue:"+name, PriorityQueue, q))
+	}
+	cfg := a.CurrentConfig()
+	for name, c := range a.Caches {
+		priority := PriorityCache
+		if cfg != nil && cfg.Cache[name].L2 != "" {
+			priority++ // composite caches close before the cache they sit on
+		}
+		hooks = append(hooks, closeHook("cache:"+name, priority, c))
+	}
+	for name, d := range a.Databases {
+		hooks = append(hooks, closeHook("db:"+name, PriorityDB, d))
+	}
+	for name, s := range a.Storages {
+		hooks = append(hooks, closeHook("storage:"+name, PriorityStorage, s))
+	}
+	return hooks
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| closeHook: Close()/Stop() as a Hook
+//||------------------------------------------------------------------------------------------------||
+
+func closeHook(name string, priority int, x any) hook {
+	return hook{name: name, priority: priority, fn: func(context.Context) error {
+		return closeIf(x)
+	}}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| runHook: return when the hook does or the context ends (a stuck Close cannot block shutdown)
+//||------------------------------------------------------------------------------------------------||
+
+func runHook(ctx context.Context, h hook) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() { done <- h.fn(ctx) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,87 @@
+//||------------------------------------------------------------------------------------------------||
+//|| App Package: Config Reload
+//|| reload.go
+//||------------------------------------------------------------------------------------------------||
+
+package app
+
+//||------------------------------------------------------------------------------------------------||
+//|| Import
+//||------------------------------------------------------------------------------------------------||
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ralphferrara/aria/config"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| ErrNoConfigSource: the application was built WithConfig, so there is nothing to reload
+//||------------------------------------------------------------------------------------------------||
+
+var ErrNoConfigSource = errors.New("app: config was passed with WithConfig, nothing to reload")
+
+//||------------------------------------------------------------------------------------------------||
+//|| CurrentConfig: the live config (read this, not the Config field, once Reload/Watch may run)
+//||------------------------------------------------------------------------------------------------||
+
+func (a *Application) CurrentConfig() *config.Config {
+	a.configMu.RLock()
+	defer a.configMu.RUnlock()
+	return a.Config
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Reload: re-read the options the application was built from, swap Config, notify subscribers
+//||
+//|| Subsystems already built (HTTP, DB, caches, ...) keep their settings; subscribe with
+//|| OnConfigChange to act on the sections you can apply live.
+//||------------------------------------------------------------------------------------------------||
+
+func (a *Application) Reload() error {
+	if a.source == nil {
+		return ErrNoConfigSource
+	}
+	return a.source.Reload()
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Watch: Reload whenever the config file (or its env overlay) changes, until ctx is done
+//||------------------------------------------------------------------------------------------------||
+
+func (a *Application) Watch(ctx context.Context) error {
+	if a.source == nil {
+		return ErrNoConfigSource
+	}
+	return a.source.Watch(ctx)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| OnConfigChange: call fn after a reload changes section ("app", "db", ...; "*" = any)
+//||
+//|| fn runs after a.Config has been swapped.
+//||------------------------------------------------------------------------------------------------||
+
+func (a *Application) OnConfigChange(section string, fn config.ChangeFunc) (cancel func()) {
+	if a.source == nil {
+		return func() {}
+	}
+	return a.source.OnChange(section, fn)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| swapConfig: publish next on a (and on the legacy global when a is the default instance)
+//||------------------------------------------------------------------------------------------------||
+
+func (a *Application) swapConfig(next *config.Config) {
+	a.configMu.Lock()
+	a.Config = next
+	a.configMu.Unlock()
+
+	defaultMu.Lock()
+	if defaultApp == a {
+		Config = next
+	}
+	defaultMu.Unlock()
+}
@@ -0,0 +1,144 @@
+//||------------------------------------------------------------------------------------------------||
+//|| App Package: Selective Bootstrap & Lazy Mode
+//|| select.go
+//||------------------------------------------------------------------------------------------------||
+
+package app
+
+//||------------------------------------------------------------------------------------------------||
+//|| Import
+//||------------------------------------------------------------------------------------------------||
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ralphferrara/aria/config"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| WithOnly: start only the listed subsystems ("db:main", "queue:main", "cache", "locale", ...)
+//||------------------------------------------------------------------------------------------------||
+
+func WithOnly(selectors ...string) Option {
+	return func(o *options) { o.only = append(o.only, selectors...) }
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| WithLazy: connect DBs, caches and queues on first use instead of during New
+//||------------------------------------------------------------------------------------------------||
+
+func WithLazy() Option {
+	return func(o *options) { o.lazy = true }
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| selection: section -> instance names (nil set = whole section)
+//||------------------------------------------------------------------------------------------------||
+
+type selection map[string]map[string]bool
+
+func (s selection) enabled(section string) bool {
+	if s == nil {
+		return true
+	}
+	_, ok := s[section]
+	return ok
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| parseSelectors
+//||------------------------------------------------------------------------------------------------||
+
+func parseSelectors(selectors []string) (selection, error) {
+	if len(selectors) == 0 {
+		return nil, nil
+	}
+	sel := selection{}
+	for _, raw := range selectors {
+		section, name, _ := strings.Cut(strings.TrimSpace(raw), ":")
+		section = strings.ToLower(section)
+		switch section {
+		case SubsystemHTTP, SubsystemStorage, SubsystemDB, SubsystemQueue, SubsystemCache, SubsystemLocale:
+		default:
+			return nil, fmt.Errorf("unknown subsystem in selector %q", raw)
+		}
+		names, seen := sel[section]
+		switch {
+		case name == "" || name == "*":
+			sel[section] = nil
+		case seen && names == nil:
+			// whole section already selected
+		case names == nil:
+			sel[section] = map[string]bool{name: true}
+		default:
+			names[name] = true
+		}
+	}
+	return sel, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| selectConfig: copy of cfg with unselected instances removed and lazy applied (cfg is untouched)
+//||------------------------------------------------------------------------------------------------||
+
+func selectConfig(cfg *config.Config, sel selection, lazy bool) (*config.Config, error) {
+	out := *cfg
+	var err error
+
+	if out.HTTP, err = pick(cfg.HTTP, sel, SubsystemHTTP, nil); err != nil {
+		return nil, err
+	}
+	if out.Storage, err = pick(cfg.Storage, sel, SubsystemStorage, nil); err != nil {
+		return nil, err
+	}
+	if out.DB, err = pick(cfg.DB, sel, SubsystemDB, func(c *config.DBInstanceConfig) { c.Lazy = c.Lazy || lazy }); err != nil {
+		return nil, err
+	}
+	if out.Queue, err = pick(cfg.Queue, sel, SubsystemQueue, func(c *config.QueueInstanceConfig) { c.Lazy = c.Lazy || lazy }); err != nil {
+		return nil, err
+	}
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Caches: a selected layered cache pulls in its L2
+	//||------------------------------------------------------------------------------------------------||
+
+	if names := sel[SubsystemCache]; names != nil {
+		for name := range names {
+			for dep := cfg.Cache[name].L2; dep != "" && !names[dep]; dep = cfg.Cache[dep].L2 {
+				names[dep] = true
+			}
+		}
+	}
+	if out.Cache, err = pick(cfg.Cache, sel, SubsystemCache, func(c *config.CacheInstanceConfig) { c.Lazy = c.Lazy || lazy }); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| pick: filter one section map and apply an optional per-instance mutation
+//||------------------------------------------------------------------------------------------------||
+
+func pick[T any](in map[string]T, sel selection, section string, apply func(*T)) (map[string]T, error) {
+	out := make(map[string]T)
+	if !sel.enabled(section) {
+		return out, nil
+	}
+	names := sel[section]
+	for name := range names {
+		if _, ok := in[name]; !ok {
+			return nil, &config.InstanceError{Section: section, Name: name, Err: fmt.Errorf("selected but not configured")}
+		}
+	}
+	for name, c := range in {
+		if names != nil && !names[name] {
+			continue
+		}
+		if apply != nil {
+			apply(&c)
+		}
+		out[name] = c
+	}
+	return out, nil
+}
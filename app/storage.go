@@ -0,0 +1,46 @@
+//||------------------------------------------------------------------------------------------------||
+//|| App Package: Storage URL Handler
+//|| storage.go
+//||------------------------------------------------------------------------------------------------||
+
+package app
+
+//||------------------------------------------------------------------------------------------------||
+//|| Import
+//||------------------------------------------------------------------------------------------------||
+
+import (
+	"fmt"
+	nethttp "net/http"
+	"net/url"
+	"strings"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| MountStorage: serve a local storage's presigned URLs on the named HTTP server
+//||
+//|| The handler is mounted at the path of the instance's public_url, so the URLs minted by
+//|| PresignGet/PresignPut resolve to it.
+//||------------------------------------------------------------------------------------------------||
+
+func (a *Application) MountStorage(storageName, httpName string) error {
+	st, ok := a.Storages[storageName]
+	if !ok || st == nil {
+		return fmt.Errorf("storage '%s' not configured", storageName)
+	}
+	h, ok := a.HTTP[httpName]
+	if !ok || h == nil {
+		return fmt.Errorf("http server '%s' not configured", httpName)
+	}
+	handler, err := st.Handler()
+	if err != nil {
+		return err
+	}
+	u, err := url.Parse(st.Config.PublicURL)
+	if err != nil || st.Config.PublicURL == "" {
+		return fmt.Errorf("storage '%s': public_url %q is not a valid URL", storageName, st.Config.PublicURL)
+	}
+	prefix := strings.TrimRight(u.Path, "/")
+	h.HandlePrefix(prefix, nethttp.StripPrefix(prefix, handler))
+	return nil
+}
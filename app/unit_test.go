@@ -0,0 +1,604 @@
+//||------------------------------------------------------------------------------------------------||
+//|| App Package: Unit Tests
+//|| unit_test.go
+//||------------------------------------------------------------------------------------------------||
+
+package app
+
+//||------------------------------------------------------------------------------------------------||
+//|| Import
+//||------------------------------------------------------------------------------------------------||
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ralphferrara/aria/cache"
+	"github.com/ralphferrara/aria/config"
+	"github.com/ralphferrara/aria/storage"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| helper: minimal valid config with no external services
+//||------------------------------------------------------------------------------------------------||
+
+func testConfig() *config.Config {
+	return &config.Config{
+		App: config.AppConfig{Name: "test", Env: "test", Port: 8080},
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test New: success without any configured services
+//||------------------------------------------------------------------------------------------------||
+
+func TestNew_Empty(t *testing.T) {
+	a, err := New(WithConfig(testConfig()))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if a.Config == nil || a.Log == nil {
+		t.Fatal("application missing config or logger")
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test New: failing instance is reported, not exited on
+//||------------------------------------------------------------------------------------------------||
+
+func TestNew_InitError(t *testing.T) {
+	cfg := testConfig()
+	cfg.Cache = map[string]config.CacheInstanceConfig{
+		"ok":     {Backend: "memory"},
+		"broken": {Backend: "memory", Eviction: "random"},
+	}
+	_, err := New(WithConfig(cfg))
+
+	var ie *InitError
+	if !errors.As(err, &ie) {
+		t.Fatalf("expected *InitError, got %T %v", err, err)
+	}
+	if ie.Subsystem != SubsystemCache || ie.Instance != "broken" {
+		t.Fatalf("InitError = %+v", ie)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test New: a missing config file is an InitError, even after another app loaded one
+//||------------------------------------------------------------------------------------------------||
+
+func TestNew_MissingConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.json")
+	if err := os.WriteFile(good, []byte(`{"app":{"name":"good","env":"test","port":8080}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(WithConfigFile(good)); err != nil {
+		t.Fatalf("New(good): %v", err)
+	}
+	_, err := New(WithConfigFile(filepath.Join(dir, "missing.json")))
+
+	var ie *InitError
+	if !errors.As(err, &ie) {
+		t.Fatalf("expected *InitError, got %T %v", err, err)
+	}
+	if ie.Subsystem != SubsystemConfig {
+		t.Fatalf("InitError = %+v, want subsystem %s", ie, SubsystemConfig)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test New: each application loads its own config file (no package singleton)
+//||------------------------------------------------------------------------------------------------||
+
+func TestNew_ConfigPerInstance(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, port int) string {
+		p := filepath.Join(dir, name+".json")
+		body := fmt.Sprintf(`{"app":{"name":%q,"env":"test","port":%d}}`, name, port)
+		if err := os.WriteFile(p, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	a, err := New(WithConfigFile(write("alpha", 8001)))
+	if err != nil {
+		t.Fatalf("New(alpha): %v", err)
+	}
+	b, err := New(WithConfigFile(write("beta", 8002)))
+	if err != nil {
+		t.Fatalf("New(beta): %v", err)
+	}
+	if a.Config.App.Name != "alpha" || a.Config.App.Port != 8001 {
+		t.Fatalf("first app config = %+v", a.Config.App)
+	}
+	if b.Config.App.Name != "beta" || b.Config.App.Port != 8002 {
+		t.Fatalf("second app got %+v, want its own file", b.Config.App)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Reload/Watch: each application reloads its own file; WithConfig apps have nothing to reload
+//||------------------------------------------------------------------------------------------------||
+
+func TestApplication_ReloadAndWatch(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, port int) string {
+		p := filepath.Join(dir, name+".json")
+		body := fmt.Sprintf(`{"app":{"name":%q,"env":"test","port":%d}}`, name, port)
+		if err := os.WriteFile(p, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	a, err := New(WithConfigFile(write("alpha", 8001)))
+	if err != nil {
+		t.Fatalf("New(alpha): %v", err)
+	}
+	b, err := New(WithConfigFile(write("beta", 8002)))
+	if err != nil {
+		t.Fatalf("New(beta): %v", err)
+	}
+
+	// Reload swaps Config before subscribers run
+	seen := make(chan int, 4)
+	defer a.OnConfigChange("app", func(_, next *config.Config) {
+		if a.CurrentConfig() != next {
+			t.Error("subscriber ran before a.Config was swapped")
+		}
+		seen <- next.App.Port
+	})()
+	write("alpha", 9001)
+	if err := a.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if port := <-seen; port != 9001 || a.CurrentConfig().App.Port != 9001 {
+		t.Fatalf("after Reload: notified %d, config %d", port, a.CurrentConfig().App.Port)
+	}
+	if b.CurrentConfig().App.Port != 8002 {
+		t.Fatalf("other application changed: %d", b.CurrentConfig().App.Port)
+	}
+
+	// Watch picks up file changes
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	if err := a.Watch(ctx); err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	write("alpha", 9002)
+	select {
+	case port := <-seen:
+		if port != 9002 || a.CurrentConfig().App.Port != 9002 {
+			t.Fatalf("after Watch: notified %d, config %d", port, a.CurrentConfig().App.Port)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("no reload after file change")
+	}
+
+	// an application built from a config value cannot reload
+	c, err := New(WithConfig(testConfig()))
+	if err != nil {
+		t.Fatalf("New(WithConfig): %v", err)
+	}
+	if err := c.Reload(); !errors.Is(err, ErrNoConfigSource) {
+		t.Fatalf("Reload = %v, want ErrNoConfigSource", err)
+	}
+	if err := c.Watch(ctx); !errors.Is(err, ErrNoConfigSource) {
+		t.Fatalf("Watch = %v, want ErrNoConfigSource", err)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Context: two applications side by side, default fallback, middleware
+//||------------------------------------------------------------------------------------------------||
+
+func TestContext_Application(t *testing.T) {
+	one, err := New(WithConfig(testConfig()))
+	if err != nil {
+		t.Fatalf("New one: %v", err)
+	}
+	two, err := New(WithConfig(testConfig()))
+	if err != nil {
+		t.Fatalf("New two: %v", err)
+	}
+
+	if got := FromContext(WithContext(context.Background(), two)); got != two {
+		t.Fatal("FromContext did not return the attached application")
+	}
+
+	SetDefault(one)
+	t.Cleanup(func() { defaultApp = nil })
+	if got := FromContext(context.Background()); got != one {
+		t.Fatal("FromContext without an application should return Default()")
+	}
+
+	var seen *Application
+	h := two.Middleware(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		seen = FromContext(r.Context())
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	if seen != two {
+		t.Fatal("Middleware did not attach the application to the request")
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test WithOnly: unselected instances are never built, layered caches pull in their L2
+//||------------------------------------------------------------------------------------------------||
+
+func TestNew_WithOnly(t *testing.T) {
+	cfg := testConfig()
+	cfg.Cache = map[string]config.CacheInstanceConfig{
+		"keep":   {Backend: "memory"},
+		"broken": {Backend: "memory", Eviction: "random"},
+	}
+	a, err := New(WithConfig(cfg), WithOnly("cache:keep"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, ok := a.Caches["keep"]; !ok || len(a.Caches) != 1 {
+		t.Fatalf("Caches = %v", a.Caches)
+	}
+
+	sel, _ := parseSelectors([]string{"cache:auth"})
+	layered := &config.Config{Cache: map[string]config.CacheInstanceConfig{
+		"auth":    {Backend: "layered", L2: "primary"},
+		"primary": {Backend: "redis"},
+		"other":   {Backend: "redis"},
+	}}
+	out, err := selectConfig(layered, sel, false)
+	if err != nil {
+		t.Fatalf("selectConfig: %v", err)
+	}
+	if _, ok := out.Cache["primary"]; !ok || len(out.Cache) != 2 {
+		t.Fatalf("layered dependency not selected: %v", out.Cache)
+	}
+
+	var ie *InitError
+	if _, err := New(WithConfig(cfg), WithOnly("db:missing")); !errors.As(err, &ie) || ie.Instance != "missing" {
+		t.Fatalf("expected InitError for unconfigured selection, got %v", err)
+	}
+	if _, err := New(WithConfig(cfg), WithOnly("nosuch:x")); err == nil {
+		t.Fatal("expected error for unknown subsystem selector")
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test WithLazy: unreachable redis does not fail startup, only the first call
+//||------------------------------------------------------------------------------------------------||
+
+func TestNew_WithLazy(t *testing.T) {
+	cfg := testConfig()
+	cfg.Cache = map[string]config.CacheInstanceConfig{
+		"down": {Backend: "redis", Host: "127.0.0.1", Port: 1, DialTimeout: 1},
+	}
+	if _, err := New(WithConfig(cfg)); err == nil {
+		t.Fatal("expected eager connect to fail")
+	}
+	a, err := New(WithConfig(cfg), WithLazy())
+	if err != nil {
+		t.Fatalf("New lazy: %v", err)
+	}
+	defer a.Shutdown(context.Background())
+	if cfg.Cache["down"].Lazy {
+		t.Fatal("WithLazy mutated the caller's config")
+	}
+	if _, err := a.Caches["down"].Get(context.Background(), "k"); err == nil {
+		t.Fatal("expected lazy cache to report the unreachable server on use")
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Lifecycle: hook ordering, aggregated errors, deadline
+//||------------------------------------------------------------------------------------------------||
+
+func TestLifecycle_Hooks(t *testing.T) {
+	a, err := New(WithConfig(testConfig()))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	var order []string
+	record := func(name string, err error) Hook {
+		return func(context.Context) error {
+			order = append(order, name)
+			return err
+		}
+	}
+	a.OnStart("late", PriorityDefault+1, record("start-late", nil))
+	a.OnStart("early", PriorityDB, record("start-early", nil))
+	if err := a.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	boom := errors.New("boom")
+	a.OnStop("db-side", PriorityDB, record("stop-db", nil))
+	a.OnStop("app", PriorityDefault, record("stop-app", boom))
+	a.OnStop("stuck", PriorityStorage, func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	err = a.Shutdown(ctx)
+	if time.Since(started) > 500*time.Millisecond {
+		t.Fatal("Shutdown ignored the deadline")
+	}
+	if !errors.Is(err, boom) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown err = %v", err)
+	}
+
+	want := []string{"start-early", "start-late", "stop-app", "stop-db"}
+	if fmt.Sprint(order) != fmt.Sprint(want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Health: parallel readiness with per-check timeout, liveness always ok
+//||------------------------------------------------------------------------------------------------||
+
+// deadlineCache reports whether its ping received the check's deadline.
+type deadlineCache struct {
+	cache.Cache
+	sawDeadline chan bool
+}
+
+func (c *deadlineCache) PingContext(ctx context.Context) error {
+	_, ok := ctx.Deadline()
+	c.sawDeadline <- ok
+	return nil
+}
+
+func TestHealth_Readiness(t *testing.T) {
+	cfg := testConfig()
+	cfg.Cache = map[string]config.CacheInstanceConfig{"main": {Backend: "memory"}}
+	a, err := New(WithConfig(cfg))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	a.ReadinessHandler(0).ServeHTTP(rec, httptest.NewRequest(nethttp.MethodGet, ReadinessPath, nil))
+	if rec.Code != nethttp.StatusOK {
+		t.Fatalf("ready status = %d, body %s", rec.Code, rec.Body.String())
+	}
+
+	a.AddCheck("slow", func(ctx context.Context) error {
+		select {
+		case <-time.After(time.Second):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	a.AddCheck("broken", func(context.Context) error { return errors.New("down") })
+	probe := &deadlineCache{sawDeadline: make(chan bool, 1)}
+	a.Caches["probe"] = probe
+
+	started := time.Now()
+	report := a.Ready(context.Background(), 50*time.Millisecond)
+	if !<-probe.sawDeadline {
+		t.Fatal("cache ping did not receive the check deadline")
+	}
+	delete(a.Caches, "probe")
+	if time.Since(started) > 500*time.Millisecond {
+		t.Fatal("checks did not run in parallel under the timeout")
+	}
+	if report.Status != StatusFail {
+		t.Fatalf("status = %q", report.Status)
+	}
+	if c := report.Components["cache:main"]; c.Status != StatusOK {
+		t.Fatalf("cache:main = %+v", c)
+	}
+	if c := report.Components["broken"]; c.Status != StatusFail || c.Error != "down" {
+		t.Fatalf("broken = %+v", c)
+	}
+	if c := report.Components["slow"]; c.Status != StatusFail {
+		t.Fatalf("slow = %+v", c)
+	}
+
+	rec = httptest.NewRecorder()
+	a.ReadinessHandler(50*time.Millisecond).ServeHTTP(rec, httptest.NewRequest(nethttp.MethodGet, ReadinessPath, nil))
+	if rec.Code != nethttp.StatusServiceUnavailable {
+		t.Fatalf("ready status = %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	a.LivenessHandler().ServeHTTP(rec, httptest.NewRequest(nethttp.MethodGet, LivenessPath, nil))
+	if rec.Code != nethttp.StatusOK {
+		t.Fatalf("live status = %d", rec.Code)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Catalog: concurrent registration and JSON export
+//||------------------------------------------------------------------------------------------------||
+
+func TestCatalog_ConcurrentExport(t *testing.T) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			Err("CatalogTest").Add(fmt.Sprintf("CODE_%d", i), "message", false)
+			Constants("CatalogTest").AddCode(fmt.Sprintf("Name %d", i), "CODE", "desc")
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		_ = Err("CatalogTest").Message("CODE_1")
+		_, _ = CatalogJSON()
+	}
+	<-done
+
+	rec := httptest.NewRecorder()
+	CatalogHandler().ServeHTTP(rec, httptest.NewRequest(nethttp.MethodGet, "/catalog", nil))
+	if rec.Code != nethttp.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	var got Catalog
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.Errors["CATALOGTEST"]["CODE_199"].Message != "message" {
+		t.Fatalf("errors export = %+v", got.Errors["CATALOGTEST"]["CODE_199"])
+	}
+	if got.Constants["CATALOGTEST"]["NAME_0"].Code != "CODE" {
+		t.Fatalf("constants export = %+v", got.Constants["CATALOGTEST"]["NAME_0"])
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Error: typed errors keep status, cause and details and match with errors.Is/As
+//||------------------------------------------------------------------------------------------------||
+
+func TestError_Typed(t *testing.T) {
+	Err("TypedTest").Add("NOT_ALLOWED", "Not allowed", false, nethttp.StatusForbidden)
+	Err("TypedTest").Add("DEFAULTED", "Defaulted", false)
+	Err("TypedTest").Add("BROKEN", "Broken", true)
+
+	cause := errors.New("db down")
+	err := fmt.Errorf("handler: %w", Err("TypedTest").New("NOT_ALLOWED").Wrap(cause).WithDetail("field", "email"))
+
+	var appErr *Error
+	if !errors.As(err, &appErr) {
+		t.Fatalf("errors.As failed for %v", err)
+	}
+	if appErr.Status != nethttp.StatusForbidden || appErr.Key() != "TYPEDTEST.NOT_ALLOWED" || appErr.Details["field"] != "email" {
+		t.Fatalf("appErr = %+v", appErr)
+	}
+	if !errors.Is(err, Err("TypedTest").Error("NOT_ALLOWED")) || !errors.Is(err, cause) {
+		t.Fatal("errors.Is did not match code or cause")
+	}
+	if errors.Is(err, Err("TypedTest").Error("DEFAULTED")) {
+		t.Fatal("errors.Is matched a different code")
+	}
+	if Err("TypedTest").New("NOT_ALLOWED").Details != nil {
+		t.Fatal("WithDetail mutated the registered entry")
+	}
+
+	if s := Err("TypedTest").New("DEFAULTED").Status; s != nethttp.StatusBadRequest {
+		t.Fatalf("default status = %d", s)
+	}
+	if s := Err("TypedTest").New("BROKEN").Status; s != nethttp.StatusInternalServerError {
+		t.Fatalf("fatal status = %d", s)
+	}
+	if msg := appErr.Localize("xx-XX"); msg != "Not allowed" {
+		t.Fatalf("Localize fallback = %q", msg)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test MountStorage: local presigned PUT/GET through the mounted handler
+//||------------------------------------------------------------------------------------------------||
+
+func TestMountStorage_Presigned(t *testing.T) {
+	cfg := testConfig()
+	cfg.HTTP = map[string]config.HTTPInstanceConfig{"api": {Backend: "mux", Port: 8081}}
+	cfg.Storage = map[string]config.StorageInstanceConfig{"files": {
+		Backend:   "local",
+		Dir:       t.TempDir(),
+		PublicURL: "http://example.test/files",
+		SignKey:   "k",
+	}}
+	a, err := New(WithConfig(cfg))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := a.MountStorage("files", "api"); err != nil {
+		t.Fatalf("MountStorage: %v", err)
+	}
+	router := a.HTTP["api"].Router
+	ctx := context.Background()
+	st := a.Storages["files"]
+
+	put, err := st.PresignPut(ctx, "up/a b.txt", time.Minute, storage.PresignConstraints{ContentType: "text/plain", ContentLength: 5})
+	if err != nil {
+		t.Fatalf("PresignPut: %v", err)
+	}
+	send := func(method, url, ct, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		if ct != "" {
+			req.Header.Set("Content-Type", ct)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	if rec := send(nethttp.MethodPut, put, "image/png", "hello"); rec.Code != nethttp.StatusBadRequest {
+		t.Fatalf("PUT with wrong type = %d", rec.Code)
+	}
+	if rec := send(nethttp.MethodPut, put, "text/plain", "hello"); rec.Code != nethttp.StatusOK {
+		t.Fatalf("PUT = %d %s", rec.Code, rec.Body.String())
+	}
+
+	get, err := st.PresignGet(ctx, "up/a b.txt", time.Minute)
+	if err != nil {
+		t.Fatalf("PresignGet: %v", err)
+	}
+	if rec := send(nethttp.MethodGet, get, "", ""); rec.Code != nethttp.StatusOK || rec.Body.String() != "hello" {
+		t.Fatalf("GET = %d %q", rec.Code, rec.Body.String())
+	}
+	if rec := send(nethttp.MethodPut, get, "text/plain", "hello"); rec.Code != nethttp.StatusMethodNotAllowed {
+		t.Fatalf("PUT on a GET URL = %d", rec.Code)
+	}
+	if rec := send(nethttp.MethodGet, strings.Replace(get, "up/a", "up/b", 1), "", ""); rec.Code != nethttp.StatusForbidden {
+		t.Fatalf("GET with tampered key = %d", rec.Code)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Schema: config.schema.json matches the structs and linked backends (go generate ./config)
+//||------------------------------------------------------------------------------------------------||
+
+func TestConfigSchema_UpToDate(t *testing.T) {
+	want, err := config.JSONSchemaJSON()
+	if err != nil {
+		t.Fatalf("JSONSchemaJSON: %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join("..", "config.schema.json"))
+	if err != nil {
+		t.Fatalf("read config.schema.json: %v", err)
+	}
+	if string(got) != string(want)+"\n" {
+		t.Fatal("config.schema.json is stale: run go generate ./config")
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Err / Constants: concurrent lookups and library creation (run with -race)
+//||------------------------------------------------------------------------------------------------||
+
+func TestLibraries_Concurrent(t *testing.T) {
+	Err("ConcurrentAuth").Add("DENIED", "access denied", false, 403)
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				if got := Err("ConcurrentAuth").Code("DENIED"); got != "CONCURRENTAUTH.DENIED" {
+					t.Errorf("Code = %q, want CONCURRENTAUTH.DENIED", got)
+					return
+				}
+				lib := fmt.Sprintf("ConcurrentLib%d", j%8)
+				Constants(lib).AddInt("N", j)
+				_ = Constants(lib).Int("N")
+			}
+		}(i)
+	}
+	wg.Wait()
+	if !ErrorsStore.HasLibrary("ConcurrentAuth") || !ConstantsStore.HasLibrary("ConcurrentLib7") {
+		t.Fatal("libraries created under contention are missing")
+	}
+}
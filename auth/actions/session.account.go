@@ -24,9 +24,10 @@ func LoadSessionAccount(r *http.Request) (http.Cookie, db.ModelAccount, types.Se
 	//|| Get the Session Cookie
 	//||------------------------------------------------------------------------------------------------||
 
+	a := app.FromContext(r.Context())
 	cookie, err := r.Cookie("session")
 	if err != nil || cookie.Value == "" {
-		app.Log.Info("LoadSessionAccount: Missing session cookie", err.Error())
+		a.Log.Info("LoadSessionAccount: Missing session cookie", err.Error())
 		return http.Cookie{}, db.ModelAccount{}, types.SessionRecord{}, app.Err("Auth").Error("MISSING_SESSION_COOKIE")
 	}
 
@@ -34,7 +35,7 @@ func LoadSessionAccount(r *http.Request) (http.Cookie, db.ModelAccount, types.Se
 	//|| Get Session
 	//||------------------------------------------------------------------------------------------------||
 
-	session, err := FetchSession(cookie.Value)
+	session, err := FetchSession(r.Context(), cookie.Value)
 	if err != nil {
 		return *cookie, db.ModelAccount{}, types.SessionRecord{}, app.Err("Auth").Error("SESSION_LOOKUP_FAILED")
 	}
@@ -48,7 +49,7 @@ func LoadSessionAccount(r *http.Request) (http.Cookie, db.ModelAccount, types.Se
 		return *cookie, db.ModelAccount{}, types.SessionRecord{}, app.Err("Auth").Error("ACCOUNT_LOOKUP_FAILED")
 	}
 
-	app.Log.Data("Session Loaded Successfully")
+	a.Log.Data("Session Loaded Successfully")
 	log.PrettyPrint(session)
 
 	//||------------------------------------------------------------------------------------------------||
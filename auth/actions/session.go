@@ -1,6 +1,7 @@
 package actions
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -17,7 +18,7 @@ import (
 //|| Get the Account Record
 //||------------------------------------------------------------------------------------------------||
 
-func SessionCreate(identifier string, account *db.ModelAccount) (string, error) {
+func SessionCreate(ctx context.Context, identifier string, account *db.ModelAccount) (string, error) {
 
 	//||------------------------------------------------------------------------------------------------||
 	//|| Generate a Random Token
@@ -50,12 +51,12 @@ func SessionCreate(identifier string, account *db.ModelAccount) (string, error)
 	}
 
 	//||------------------------------------------------------------------------------------------------||
-	//|| Save to Redis
+	//|| Save to Cache
 	//||------------------------------------------------------------------------------------------------||
 
-	err = app.CacheRedis["auth"].Set("session::"+sessionToken, sessionJSON, 30*24*time.Hour)
+	err = app.FromContext(ctx).Caches["auth"].Set(ctx, "session::"+sessionToken, sessionJSON, 30*24*time.Hour)
 	if err != nil {
-		fmt.Println("[Session] Failed to save session to Redis:", err)
+		fmt.Println("[Session] Failed to save session to cache:", err)
 		return "", err
 	}
 	//||------------------------------------------------------------------------------------------------||
@@ -70,12 +71,12 @@ func SessionCreate(identifier string, account *db.ModelAccount) (string, error)
 //|| Fetch Session
 //||------------------------------------------------------------------------------------------------||
 
-func FetchSession(sessionID string) (types.SessionRecord, error) {
+func FetchSession(ctx context.Context, sessionID string) (types.SessionRecord, error) {
 	//||------------------------------------------------------------------------------------------------||
 	//|| Get the Session from the Database
 	//||------------------------------------------------------------------------------------------------||
 
-	sessionJSON, err := app.CacheRedis["auth"].Get("session::" + sessionID)
+	sessionJSON, err := app.FromContext(ctx).Caches["auth"].Get(ctx, "session::"+sessionID)
 	if err != nil {
 		return types.SessionRecord{}, fmt.Errorf("failed to fetch session: %w", err)
 	}
@@ -85,7 +86,7 @@ func FetchSession(sessionID string) (types.SessionRecord, error) {
 	//||------------------------------------------------------------------------------------------------||
 
 	var session types.SessionRecord
-	if err := json.Unmarshal([]byte(sessionJSON), &session); err != nil {
+	if err := json.Unmarshal(sessionJSON, &session); err != nil {
 		return types.SessionRecord{}, fmt.Errorf("failed to unmarshal session: %w", err)
 	}
 
@@ -108,7 +109,7 @@ func FetchSession(sessionID string) (types.SessionRecord, error) {
 //|| Update the Session
 //||------------------------------------------------------------------------------------------------||
 
-func UpdateSession(sessionToken string, session types.SessionRecord) error {
+func UpdateSession(ctx context.Context, sessionToken string, session types.SessionRecord) error {
 
 	//||------------------------------------------------------------------------------------------------||
 	//|| Marshal Session Data
@@ -121,12 +122,12 @@ func UpdateSession(sessionToken string, session types.SessionRecord) error {
 	}
 
 	//||------------------------------------------------------------------------------------------------||
-	//|| Save to Redis (overwrite)
+	//|| Save to Cache (overwrite)
 	//||------------------------------------------------------------------------------------------------||
 
-	err = app.CacheRedis["auth"].Set("session::"+sessionToken, sessionJSON, 30*24*time.Hour)
+	err = app.FromContext(ctx).Caches["auth"].Set(ctx, "session::"+sessionToken, sessionJSON, 30*24*time.Hour)
 	if err != nil {
-		fmt.Println("[Session] Failed to update session in Redis:", err)
+		fmt.Println("[Session] Failed to update session in cache:", err)
 		return err
 	}
 
@@ -142,14 +143,15 @@ func UpdateSession(sessionToken string, session types.SessionRecord) error {
 //|| Create and Set Session Cookies
 //||------------------------------------------------------------------------------------------------||
 
-func WriteSessionCookie(w http.ResponseWriter, sessionToken string) {
+func WriteSessionCookie(w http.ResponseWriter, r *http.Request, sessionToken string) {
 
 	if sessionToken == "" {
 		fmt.Println("[Session] No session token provided")
 		return
 	}
 
-	app.Log.Data("[Session] Setting cookie with token:", sessionToken)
+	a := app.FromContext(r.Context())
+	a.Log.Data("[Session] Setting cookie with token:", sessionToken)
 
 	//||------------------------------------------------------------------------------------------------||
 	//|| Helper: Build Cookie
@@ -164,7 +166,7 @@ func WriteSessionCookie(w http.ResponseWriter, sessionToken string) {
 			MaxAge:   86400 * 30, // 30 days
 		}
 
-		if app.Config.App.Env == "production" {
+		if a.InProduction() {
 			// Production: explicit domain + HTTPS
 			c.Domain = setup.Setup.Domain // e.g. "complyage.com"
 			c.Secure = true
@@ -189,8 +191,8 @@ func WriteSessionCookie(w http.ResponseWriter, sessionToken string) {
 //|| Delete Session
 //||------------------------------------------------------------------------------------------------||
 
-func DeleteSession(sessionToken string) error {
-	return app.CacheRedis["auth"].Del("session::" + sessionToken)
+func DeleteSession(ctx context.Context, sessionToken string) error {
+	return app.FromContext(ctx).Caches["auth"].Delete(ctx, "session::"+sessionToken)
 }
 
 //||------------------------------------------------------------------------------------------------||
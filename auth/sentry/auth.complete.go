@@ -30,12 +30,13 @@ type authCompleteResponse struct {
 //||------------------------------------------------------------------------------------------------||
 
 func CompleteHandler(w http.ResponseWriter, r *http.Request) {
+	a := app.FromContext(r.Context())
 
 	//||------------------------------------------------------------------------------------------------||
 	//|| DB Account
 	//||------------------------------------------------------------------------------------------------||
 
-	app.Log.Info("Auth Complete Invoked - Now")
+	a.Log.Info("Auth Complete Invoked - Now")
 	cookie, dbAccount, session, err := actions.LoadSessionAccount(r)
 	if err != nil {
 		responses.Error(w, http.StatusUnauthorized, err.Error())
@@ -46,7 +47,7 @@ func CompleteHandler(w http.ResponseWriter, r *http.Request) {
 	//|| Check Account Status
 	//||------------------------------------------------------------------------------------------------||
 
-	// app.Log.Info("dbAccount.Status:", dbAccount.Status)
+	// a.Log.Info("dbAccount.Status:", dbAccount.Status)
 	// if dbAccount.Status != app.Constants("AccountStatus").Code("Verified") {
 	// 	responses.Error(w, http.StatusForbidden, app.Err("Auth").Code("ACCOUNT_ALREADY_CREATED"))
 	// 	return
@@ -65,7 +66,7 @@ func CompleteHandler(w http.ResponseWriter, r *http.Request) {
 	//||
 	//||------------------------------------------------------------------------------------------------||
 
-	app.Log.Info("Sanitizing and Validating Input")
+	a.Log.Info("Sanitizing and Validating Input")
 	vp := validate.IsValidPassword(password)
 	if vp != nil {
 		responses.Error(w, http.StatusBadRequest, vp.Error())
@@ -76,7 +77,7 @@ func CompleteHandler(w http.ResponseWriter, r *http.Request) {
 	//|| Password/Salt
 	//||------------------------------------------------------------------------------------------------||
 
-	app.Log.Info("Generating Password/Salt")
+	a.Log.Info("Generating Password/Salt")
 	passwordHash, saltHash := actions.GeneratePassword(password)
 	if passwordHash == "" {
 		responses.Error(w, http.StatusBadRequest, app.Err("Auth").Code("PASSWORD_GEN_FAILED"))
@@ -86,8 +87,8 @@ func CompleteHandler(w http.ResponseWriter, r *http.Request) {
 	//|| Create the Account Record
 	//||------------------------------------------------------------------------------------------------||
 
-	app.Log.Info("Creating account for:", session.Identifier)
-	app.Log.Info(actions.GenerateIdentifierHash(session.Identifier))
+	a.Log.Info("Creating account for:", session.Identifier)
+	a.Log.Info(actions.GenerateIdentifierHash(session.Identifier))
 	account := db.ModelAccount{}
 	account.ID = dbAccount.ID
 	account.Identifier = actions.GenerateIdentifierHash(session.Identifier)
@@ -101,7 +102,7 @@ func CompleteHandler(w http.ResponseWriter, r *http.Request) {
 	//|| Run the Complete Func
 	//||------------------------------------------------------------------------------------------------||
 
-	app.Log.Info("Running OnAccountComplete")
+	a.Log.Info("Running OnAccountComplete")
 	err = setup.Setup.Functions.OnAccountComplete(r, account.ID, session.Identifier)
 	if err != nil {
 		responses.Error(w, http.StatusInternalServerError, err.Error())
@@ -112,7 +113,7 @@ func CompleteHandler(w http.ResponseWriter, r *http.Request) {
 	//|| Refetch the User Data
 	//||------------------------------------------------------------------------------------------------||
 
-	app.Log.Info("Re-fetching the updated account")
+	a.Log.Info("Re-fetching the updated account")
 	updatedAccount, err := db.GetAccountByID(fmt.Sprintf("%d", account.ID))
 	if err != nil || updatedAccount == nil {
 		responses.Error(w, http.StatusInternalServerError, app.Err("Auth").Code("ACCOUNT_LOOKUP_FAILED"))
@@ -123,7 +124,7 @@ func CompleteHandler(w http.ResponseWriter, r *http.Request) {
 	//|| Create the Session
 	//||------------------------------------------------------------------------------------------------||
 
-	sessionToken, err := actions.SessionCreate(updatedAccount.Identifier, updatedAccount)
+	sessionToken, err := actions.SessionCreate(r.Context(), updatedAccount.Identifier, updatedAccount)
 	if err != nil || sessionToken == "" {
 		responses.Error(w, http.StatusInternalServerError, app.Err("Auth").Code("SESSION_GEN_FAILED"))
 		return
@@ -143,14 +144,14 @@ func CompleteHandler(w http.ResponseWriter, r *http.Request) {
 	//|| Write the Session Cookie
 	//||------------------------------------------------------------------------------------------------||
 
-	actions.WriteSessionCookie(w, sessionToken)
+	actions.WriteSessionCookie(w, r, sessionToken)
 
 	//||------------------------------------------------------------------------------------------------||
 	//|| Delete the Old Session Cookie
 	//||------------------------------------------------------------------------------------------------||
 
 	if cookie.Value != "" && cookie.Value != sessionToken {
-		_ = actions.DeleteSession(cookie.Value)
+		_ = actions.DeleteSession(r.Context(), cookie.Value)
 	}
 
 	//||------------------------------------------------------------------------------------------------||
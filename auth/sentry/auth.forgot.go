@@ -31,6 +31,7 @@ type responseForgotPassword struct {
 //||------------------------------------------------------------------------------------------------||
 
 func ForgotPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	a := app.FromContext(r.Context())
 
 	//||------------------------------------------------------------------------------------------------||
 	//|| Var
@@ -92,7 +93,7 @@ func ForgotPasswordHandler(w http.ResponseWriter, r *http.Request) {
 	//|| Save Reset Request in Redis (15 min expiry)
 	//||------------------------------------------------------------------------------------------------||
 
-	err = app.CacheRedis["auth"].Set("reset::"+keyEncoded, data, 15*time.Minute)
+	err = a.Caches["auth"].Set(r.Context(), "reset::"+keyEncoded, data, 15*time.Minute)
 	if err != nil {
 		responses.Error(w, http.StatusInternalServerError, "Failed to store reset request")
 		return
@@ -91,9 +91,9 @@ func LoginHandler(w http.ResponseWriter, r *http.Request) {
 	//|| Get the Account Record and redirect if completed
 	//||------------------------------------------------------------------------------------------------||
 
-	loginToken, err := actions.SessionCreate(identifier, account)
+	loginToken, err := actions.SessionCreate(r.Context(), identifier, account)
 	if err == nil {
-		actions.WriteSessionCookie(w, loginToken)
+		actions.WriteSessionCookie(w, r, loginToken)
 		responses.Success(w, http.StatusOK, loginResponse{
 			Next: "/members/",
 		})
@@ -38,7 +38,7 @@ func LogoutHandler(w http.ResponseWriter, r *http.Request) {
 	//|| Delete Session from Redis
 	//||------------------------------------------------------------------------------------------------||
 
-	err = actions.DeleteSession(cookie.Value)
+	err = actions.DeleteSession(r.Context(), cookie.Value)
 	if err != nil {
 		fmt.Printf("[Logout] Failed to delete session %s from Redis: %v\n", cookie.Value, err)
 	}
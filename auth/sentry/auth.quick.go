@@ -39,7 +39,7 @@ func QuickCheckHandler(w http.ResponseWriter, r *http.Request) {
 	//|| Get Session
 	//||------------------------------------------------------------------------------------------------||
 
-	_, sErr := actions.FetchSession(cookie.Value)
+	_, sErr := actions.FetchSession(r.Context(), cookie.Value)
 	if sErr != nil {
 		responses.Error(w, http.StatusUnauthorized, app.Err("Auth").Code("SESSION_LOOKUP_FAILED"))
 		return
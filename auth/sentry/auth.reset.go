@@ -33,7 +33,7 @@ func ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	session, err := actions.FetchSession(cookie.Value)
+	session, err := actions.FetchSession(r.Context(), cookie.Value)
 
 	if err != nil {
 		responses.Error(w, http.StatusUnauthorized, "Invalid session")
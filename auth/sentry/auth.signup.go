@@ -34,6 +34,7 @@ type signupResponse struct {
 //||------------------------------------------------------------------------------------------------||
 
 func SignupHandler(w http.ResponseWriter, r *http.Request) {
+	a := app.FromContext(r.Context())
 	//||------------------------------------------------------------------------------------------------||
 	//|| Var
 	//||------------------------------------------------------------------------------------------------||
@@ -83,12 +84,12 @@ func SignupHandler(w http.ResponseWriter, r *http.Request) {
 	//|| Save to Redis with expiry
 	//||------------------------------------------------------------------------------------------------||
 
-	err = app.CacheRedis["auth"].Set(actions.TwoFactorCacheCode(key), data, 15*time.Minute)
+	err = a.Caches["auth"].Set(r.Context(), actions.TwoFactorCacheCode(key), data, 15*time.Minute)
 	if err != nil {
 		responses.Error(w, http.StatusInternalServerError, "Failed to cache verification")
 		return
 	}
-	app.Log.Data(fmt.Sprintf("TwoFactor code=%s\n", code))
+	a.Log.Data(fmt.Sprintf("TwoFactor code=%s\n", code))
 
 	//||------------------------------------------------------------------------------------------------||
 	//|| Send
@@ -34,6 +34,7 @@ type responseTwoFactor struct {
 //||------------------------------------------------------------------------------------------------||
 
 func TwoFactorHandler(w http.ResponseWriter, r *http.Request) {
+	a := app.FromContext(r.Context())
 	//||------------------------------------------------------------------------------------------------||
 	//|| Var
 	//||------------------------------------------------------------------------------------------------||
@@ -45,7 +46,7 @@ func TwoFactorHandler(w http.ResponseWriter, r *http.Request) {
 	//|| Var
 	//||------------------------------------------------------------------------------------------------||
 
-	app.Log.Data("[TwoFactor] Incoming -> token:", token, " code:", code)
+	a.Log.Data("[TwoFactor] Incoming -> token:", token, " code:", code)
 
 	//||------------------------------------------------------------------------------------------------||
 	//|| Basic Validation
@@ -60,7 +61,7 @@ func TwoFactorHandler(w http.ResponseWriter, r *http.Request) {
 	//|| Get Record from Redis
 	//||------------------------------------------------------------------------------------------------||
 
-	val, err := app.CacheRedis["auth"].Get(actions.TwoFactorCacheCode(token))
+	val, err := a.Caches["auth"].Get(r.Context(), actions.TwoFactorCacheCode(token))
 	if err != nil {
 		responses.Error(w, http.StatusBadRequest, app.Err("Auth").Code("TF_INVALID_TOKEN"))
 		return
@@ -71,12 +72,12 @@ func TwoFactorHandler(w http.ResponseWriter, r *http.Request) {
 	//||------------------------------------------------------------------------------------------------||
 
 	var record types.TwoFactorVerification
-	if err := json.Unmarshal([]byte(val), &record); err != nil {
+	if err := json.Unmarshal(val, &record); err != nil {
 		responses.Error(w, http.StatusInternalServerError, app.Err("Auth").Code("TF_INVALID_RECORD"))
 		return
 	}
 
-	app.Log.Data("[TwoFactor] Record:")
+	a.Log.Data("[TwoFactor] Record:")
 	log.PrettyPrint(record)
 
 	//||------------------------------------------------------------------------------------------------||
@@ -84,7 +85,7 @@ func TwoFactorHandler(w http.ResponseWriter, r *http.Request) {
 	//||------------------------------------------------------------------------------------------------||
 
 	if record.Attempts >= 5 {
-		app.CacheRedis["auth"].Del(fmt.Sprintf("verify:%s", token))
+		a.Caches["auth"].Delete(r.Context(), fmt.Sprintf("verify:%s", token))
 		responses.Error(w, http.StatusTooManyRequests, app.Err("Auth").Code("TF_TOO_MANY_ATTEMPTS"))
 		return
 	}
@@ -96,7 +97,7 @@ func TwoFactorHandler(w http.ResponseWriter, r *http.Request) {
 	if code != record.Code {
 		record.Attempts++
 		newData, _ := json.Marshal(record)
-		app.CacheRedis["auth"].Set(fmt.Sprintf("verify:%s", token), newData, time.Until(record.Expires))
+		a.Caches["auth"].Set(r.Context(), fmt.Sprintf("verify:%s", token), newData, time.Until(record.Expires))
 		responses.Error(w, http.StatusUnauthorized, app.Err("Auth").Code("TF_CODE_MISMATCH"))
 		return
 	}
@@ -106,7 +107,7 @@ func TwoFactorHandler(w http.ResponseWriter, r *http.Request) {
 	//||------------------------------------------------------------------------------------------------||
 
 	if time.Now().After(record.Expires) {
-		app.CacheRedis["auth"].Del(fmt.Sprintf("verify:%s", token))
+		a.Caches["auth"].Delete(r.Context(), fmt.Sprintf("verify:%s", token))
 		responses.Error(w, http.StatusBadRequest, app.Err("Auth").Code("TF_TOKEN_EXPIRED"))
 		return
 	}
@@ -115,7 +116,7 @@ func TwoFactorHandler(w http.ResponseWriter, r *http.Request) {
 	//|| Success! Delete
 	//||------------------------------------------------------------------------------------------------||
 
-	app.CacheRedis["auth"].Del(fmt.Sprintf("verify:%s", token))
+	a.Caches["auth"].Delete(r.Context(), fmt.Sprintf("verify:%s", token))
 	fmt.Println("Successfully validated Record:", record.Type)
 
 	//||------------------------------------------------------------------------------------------------||
@@ -146,9 +147,9 @@ func TwoFactorHandler(w http.ResponseWriter, r *http.Request) {
 		//|| Create the Session and redirect to reset password
 		//||------------------------------------------------------------------------------------------------||
 
-		existsToken, err := actions.SessionCreate(account.Identifier, account)
+		existsToken, err := actions.SessionCreate(r.Context(), account.Identifier, account)
 		if err == nil {
-			actions.WriteSessionCookie(w, existsToken)
+			actions.WriteSessionCookie(w, r, existsToken)
 			responses.Success(w, http.StatusOK, responseTwoFactor{
 				Message: "OK",
 				Next:    "/reset",
@@ -211,10 +212,10 @@ func TwoFactorHandler(w http.ResponseWriter, r *http.Request) {
 	//|| Create the Account Record
 	//||------------------------------------------------------------------------------------------------||
 
-	newToken, err := actions.SessionCreate(record.Identifier, account)
+	newToken, err := actions.SessionCreate(r.Context(), record.Identifier, account)
 	fmt.Println("Session Create:", newToken, err)
 	if err == nil {
-		actions.WriteSessionCookie(w, newToken)
+		actions.WriteSessionCookie(w, r, newToken)
 		responses.Success(w, http.StatusOK, responseTwoFactor{
 			Message: "OK",
 			Next:    nextPage,
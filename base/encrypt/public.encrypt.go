@@ -62,7 +62,7 @@ func EncryptWithPublicKey(data []byte, publicKeyPEM string) ([]byte, error) {
 
 	pemBlock, _ := pem.Decode([]byte(publicKeyPEM))
 	if pemBlock == nil || pemBlock.Type != "PUBLIC KEY" {
-		app.Log.Error("Invalid PEM Block:", publicKeyPEM)
+		app.Default().Log.Error("Invalid PEM Block:", publicKeyPEM)
 		return nil, errors.New("invalid public key PEM format (expected PKIX PUBLIC KEY)")
 	}
 
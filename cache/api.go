@@ -6,84 +6,253 @@
 package cache
 
 import (
+	"context"
+	"errors"
 	"time"
 
 	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/go-redis/redis/v8"
 )
 
 //||------------------------------------------------------------------------------------------------||
-//|| Redis/KeyDB: Set Key
+//|| Redis/KeyDB: Get Key
 //||------------------------------------------------------------------------------------------------||
 
-func (c *RedisCacheWrapper) Set(key string, value interface{}, ttl time.Duration) error {
-	return c.Client.Set(c.Ctx, key, value, ttl).Err()
+func (c *RedisCacheWrapper) Get(ctx context.Context, key string) ([]byte, error) {
+	val, err := c.Client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotFound
+	}
+	return val, err
 }
 
 //||------------------------------------------------------------------------------------------------||
-//|| Redis/KeyDB: Get Key
+//|| Redis/KeyDB: Set Key
 //||------------------------------------------------------------------------------------------------||
 
-func (c *RedisCacheWrapper) Get(key string) (string, error) {
-	return c.Client.Get(c.Ctx, key).Result()
+func (c *RedisCacheWrapper) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.Client.Set(ctx, key, value, ttl).Err()
 }
 
 //||------------------------------------------------------------------------------------------------||
 //|| Redis/KeyDB: Delete Key
 //||------------------------------------------------------------------------------------------------||
 
-func (c *RedisCacheWrapper) Del(key string) error {
-	return c.Client.Del(c.Ctx, key).Err()
+func (c *RedisCacheWrapper) Delete(ctx context.Context, key string) error {
+	return c.Client.Del(ctx, key).Err()
 }
 
 //||------------------------------------------------------------------------------------------------||
-//|| Memcached: Set Key
+//|| Redis/KeyDB: Exists
 //||------------------------------------------------------------------------------------------------||
 
-func (c *MemcachedCacheWrapper) Set(key string, value []byte, ttl int32) error {
-	return c.Client.Set(&memcache.Item{Key: key, Value: value, Expiration: ttl})
+func (c *RedisCacheWrapper) Exists(ctx context.Context, key string) (bool, error) {
+	n, err := c.Client.Exists(ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Redis/KeyDB: TTL (0 = no expiry)
+//||------------------------------------------------------------------------------------------------||
+
+func (c *RedisCacheWrapper) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := c.Client.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	switch ttl {
+	case -2:
+		return 0, ErrNotFound
+	case -1:
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Redis/KeyDB: Close
+//||------------------------------------------------------------------------------------------------||
+
+func (c *RedisCacheWrapper) Close() error {
+	if c == nil || c.Client == nil {
+		return nil
+	}
+	return c.Client.Close()
 }
 
 //||------------------------------------------------------------------------------------------------||
 //|| Memcached: Get Key
 //||------------------------------------------------------------------------------------------------||
 
-func (c *MemcachedCacheWrapper) Get(key string) ([]byte, error) {
+func (c *MemcachedCacheWrapper) Get(ctx context.Context, key string) ([]byte, error) {
 	item, err := c.Client.Get(key)
+	if errors.Is(err, memcache.ErrCacheMiss) {
+		return nil, ErrNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 	return item.Value, nil
 }
 
+//||------------------------------------------------------------------------------------------------||
+//|| Memcached: Set Key
+//||------------------------------------------------------------------------------------------------||
+
+func (c *MemcachedCacheWrapper) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.Client.Set(&memcache.Item{Key: key, Value: value, Expiration: memcachedExpiration(ttl)})
+}
+
 //||------------------------------------------------------------------------------------------------||
 //|| Memcached: Delete Key
 //||------------------------------------------------------------------------------------------------||
 
-func (c *MemcachedCacheWrapper) Del(key string) error {
-	return c.Client.Delete(key)
+func (c *MemcachedCacheWrapper) Delete(ctx context.Context, key string) error {
+	err := c.Client.Delete(key)
+	if errors.Is(err, memcache.ErrCacheMiss) {
+		return nil
+	}
+	return err
 }
 
 //||------------------------------------------------------------------------------------------------||
-//|| Memory: Set Key
+//|| Memcached: Exists
+//||------------------------------------------------------------------------------------------------||
+
+func (c *MemcachedCacheWrapper) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := c.Get(ctx, key)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Memcached: TTL (not exposed by the protocol)
+//||------------------------------------------------------------------------------------------------||
+
+func (c *MemcachedCacheWrapper) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return 0, ErrUnsupported
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Memcached: Close
 //||------------------------------------------------------------------------------------------------||
 
-func (c *MemoryCacheWrapper) Set(key string, value interface{}) {
-	c.Store[key] = value
+func (c *MemcachedCacheWrapper) Close() error {
+	if c == nil || c.Client == nil {
+		return nil
+	}
+	return c.Client.Close()
 }
 
 //||------------------------------------------------------------------------------------------------||
 //|| Memory: Get Key
 //||------------------------------------------------------------------------------------------------||
 
-func (c *MemoryCacheWrapper) Get(key string) (interface{}, bool) {
-	v, ok := c.Store[key]
-	return v, ok
+func (c *MemoryCacheWrapper) Get(ctx context.Context, key string) ([]byte, error) {
+	s, err := c.shard(key)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.get(key, time.Now())
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), entry.Value...), nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Memory: Set Key
+//||------------------------------------------------------------------------------------------------||
+
+func (c *MemoryCacheWrapper) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	entry := &memoryEntry{Key: key, Value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		entry.Expires = time.Now().Add(ttl)
+	}
+	s, err := c.shard(key)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.set(entry)
+	return nil
 }
 
 //||------------------------------------------------------------------------------------------------||
 //|| Memory: Delete Key
 //||------------------------------------------------------------------------------------------------||
 
-func (c *MemoryCacheWrapper) Del(key string) {
-	delete(c.Store, key)
+func (c *MemoryCacheWrapper) Delete(ctx context.Context, key string) error {
+	s, err := c.shard(key)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delete(key)
+	return nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Memory: Exists
+//||------------------------------------------------------------------------------------------------||
+
+func (c *MemoryCacheWrapper) Exists(ctx context.Context, key string) (bool, error) {
+	s, err := c.shard(key)
+	if err != nil {
+		return false, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.peek(key, time.Now())
+	return ok, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Memory: TTL (0 = no expiry)
+//||------------------------------------------------------------------------------------------------||
+
+func (c *MemoryCacheWrapper) TTL(ctx context.Context, key string) (time.Duration, error) {
+	now := time.Now()
+	s, err := c.shard(key)
+	if err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.peek(key, now)
+	if !ok {
+		return 0, ErrNotFound
+	}
+	if entry.Expires.IsZero() {
+		return 0, nil
+	}
+	return entry.Expires.Sub(now), nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Memory: Close (stops the expiry sweeper)
+//||------------------------------------------------------------------------------------------------||
+
+func (c *MemoryCacheWrapper) Close() error {
+	if c == nil {
+		return nil
+	}
+	c.closeOnce.Do(func() {
+		if c.stop != nil {
+			close(c.stop)
+		}
+	})
+	return nil
 }
@@ -7,34 +7,159 @@ package cache
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/bradfitz/gomemcache/memcache"
 	"github.com/go-redis/redis/v8"
 	"github.com/ralphferrara/aria/config"
 )
 
+//||------------------------------------------------------------------------------------------------||
+//|| Redis Modes
+//||------------------------------------------------------------------------------------------------||
+
+const (
+	RedisModeSingle   = "single"
+	RedisModeSentinel = "sentinel"
+	RedisModeCluster  = "cluster"
+)
+
 //||------------------------------------------------------------------------------------------------||
 //|| Build Redis/KeyDB Options
 //||------------------------------------------------------------------------------------------------||
 
-func buildRedisOptions(cfg config.CacheInstanceConfig) *redis.Options {
-	return &redis.Options{
-		Addr:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
-		Password: cfg.Password,
-		DB:       cfg.DB,
+func buildRedisOptions(cfg config.CacheInstanceConfig) (*redis.UniversalOptions, error) {
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Common
+	//||------------------------------------------------------------------------------------------------||
+
+	opts := &redis.UniversalOptions{
+		Username:         cfg.Username,
+		Password:         cfg.Password,
+		SentinelPassword: cfg.SentinelPassword,
+		DB:               cfg.DB,
+		PoolSize:         cfg.PoolSize,
+		MinIdleConns:     cfg.MinIdleConns,
+		MaxRetries:       cfg.MaxRetries,
+		DialTimeout:      time.Duration(cfg.DialTimeout) * time.Second,
+		PoolTimeout:      time.Duration(cfg.PoolTimeout) * time.Second,
+	}
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Addresses
+	//||------------------------------------------------------------------------------------------------||
+
+	switch redisMode(cfg) {
+	case RedisModeSentinel:
+		if cfg.MasterName == "" || len(cfg.SentinelAddrs) == 0 {
+			return nil, fmt.Errorf("sentinel mode requires master_name and sentinel_addrs")
+		}
+		opts.MasterName = cfg.MasterName
+		opts.Addrs = cfg.SentinelAddrs
+	case RedisModeCluster:
+		if len(cfg.Servers) == 0 {
+			return nil, fmt.Errorf("cluster mode requires servers")
+		}
+		opts.Addrs = cfg.Servers
+	case RedisModeSingle:
+		opts.Addrs = []string{fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)}
+	default:
+		return nil, fmt.Errorf("unsupported redis mode: %s", cfg.Mode)
 	}
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| TLS
+	//||------------------------------------------------------------------------------------------------||
+
+	if cfg.TLS {
+		tlsCfg, err := buildTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		opts.TLSConfig = tlsCfg
+	}
+
+	return opts, nil
 }
 
 //||------------------------------------------------------------------------------------------------||
-//|| Connect Redis/KeyDB
+//|| Redis Mode (defaults to single)
 //||------------------------------------------------------------------------------------------------||
 
-func connectRedis(cfg config.CacheInstanceConfig) (*redis.Client, context.Context, error) {
-	opts := buildRedisOptions(cfg)
+func redisMode(cfg config.CacheInstanceConfig) string {
+	if cfg.Mode == "" {
+		return RedisModeSingle
+	}
+	return strings.ToLower(cfg.Mode)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Build TLS Config (optional client cert + custom CA)
+//||------------------------------------------------------------------------------------------------||
+
+func buildTLSConfig(cfg config.CacheInstanceConfig) (*tls.Config, error) {
+	tlsCfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         cfg.TLSServerName,
+		InsecureSkipVerify: cfg.TLSSkipVerify,
+	}
+	if cfg.TLSCertFile != "" || cfg.TLSKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load tls client cert: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	if cfg.TLSCAFile != "" {
+		pem, err := os.ReadFile(cfg.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read tls ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("tls ca file contains no certificates: %s", cfg.TLSCAFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+	return tlsCfg, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Connect Redis/KeyDB (single, sentinel failover or cluster)
+//||------------------------------------------------------------------------------------------------||
+
+func connectRedis(cfg config.CacheInstanceConfig) (redis.UniversalClient, context.Context, error) {
+	opts, err := buildRedisOptions(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
 	ctx := context.Background()
-	client := redis.NewClient(opts)
+
+	var client redis.UniversalClient
+	switch redisMode(cfg) {
+	case RedisModeSentinel:
+		client = redis.NewFailoverClient(opts.Failover())
+	case RedisModeCluster:
+		client = redis.NewClusterClient(opts.Cluster())
+	default:
+		client = redis.NewClient(opts.Simple())
+	}
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Lazy: go-redis dials on first command and redials with backoff, so skip the startup ping
+	//||------------------------------------------------------------------------------------------------||
+
+	if cfg.Lazy {
+		return client, ctx, nil
+	}
 	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
 		return nil, nil, err
 	}
 	return client, ctx, nil
@@ -52,6 +177,9 @@ func connectMemcached(cfg config.CacheInstanceConfig) (*memcache.Client, error)
 		servers = []string{fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)}
 	}
 	client := memcache.New(servers...)
+	if cfg.Lazy {
+		return client, nil
+	}
 	err := client.Ping()
 	if err != nil {
 		return nil, err
@@ -60,9 +188,27 @@ func connectMemcached(cfg config.CacheInstanceConfig) (*memcache.Client, error)
 }
 
 //||------------------------------------------------------------------------------------------------||
-//|| Init Memory Store
+//|| Memory Entry: Expired
 //||------------------------------------------------------------------------------------------------||
 
-func initMemoryStore() map[string]interface{} {
-	return make(map[string]interface{})
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.Expires.IsZero() && now.After(e.Expires)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Memcached Expiration (seconds, or unix time beyond 30 days)
+//||------------------------------------------------------------------------------------------------||
+
+func memcachedExpiration(ttl time.Duration) int32 {
+	if ttl <= 0 {
+		return 0
+	}
+	if ttl > 30*24*time.Hour {
+		return int32(time.Now().Add(ttl).Unix())
+	}
+	secs := int32(ttl / time.Second)
+	if secs < 1 {
+		secs = 1
+	}
+	return secs
 }
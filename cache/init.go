@@ -6,106 +6,85 @@
 package cache
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/ralphferrara/aria/config"
 )
 
 //||------------------------------------------------------------------------------------------------||
-//|| Init (build all caches from main config)
+//|| Init (build all caches from main config, keyed by name)
 //||------------------------------------------------------------------------------------------------||
 
-func Init(cfg *config.Config) (
-	map[string]*RedisCacheWrapper,
-	map[string]*RedisCacheWrapper,
-	map[string]*MemcachedCacheWrapper,
-	map[string]*MemoryCacheWrapper,
-	error,
-) {
+func Init(cfg *config.Config) (map[string]Cache, error) {
 
 	//||------------------------------------------------------------------------------------------------||
-	//|| Output Maps
+	//|| Output Map + Build State (dependencies are built first, cycles are rejected)
 	//||------------------------------------------------------------------------------------------------||
 
-	redisMap := make(map[string]*RedisCacheWrapper)
-	keydbMap := make(map[string]*RedisCacheWrapper)
-	memcMap := make(map[string]*MemcachedCacheWrapper)
-	memMap := make(map[string]*MemoryCacheWrapper)
+	caches := make(map[string]Cache)
+	building := make(map[string]bool)
+
+	var build Resolver
+	build = func(name string) (Cache, error) {
+		if c, ok := caches[name]; ok {
+			return c, nil
+		}
+		c, ok := cfg.Cache[name]
+		if !ok {
+			return nil, fmt.Errorf("cache '%s' is not configured", name)
+		}
+		if building[name] {
+			return nil, fmt.Errorf("cache '%s' has a dependency cycle", name)
+		}
+		factory, ok := lookup(c.Backend)
+		if !ok {
+			return nil, &config.InstanceError{Section: config.SectionCache, Name: name, Err: fmt.Errorf("unsupported cache backend: %s", c.Backend)}
+		}
+		building[name] = true
+		built, err := factory(name, c, build)
+		delete(building, name)
+		if err != nil {
+			return nil, &config.InstanceError{Section: config.SectionCache, Name: name, Err: fmt.Errorf("%s: %w", c.Backend, err)}
+		}
+		fmt.Printf("\n[CACH] - Initializing cache: %s (backend: %s)", name, c.Backend)
+		caches[name] = built
+		return built, nil
+	}
 
 	//||------------------------------------------------------------------------------------------------||
-	//|| Loop Configured Caches
+	//|| Loop Configured Caches (sorted so startup order is stable)
 	//||------------------------------------------------------------------------------------------------||
 
-	for name, c := range cfg.Cache {
-		switch c.Backend {
-
-		//||------------------------------------------------------------------------------------------------||
-		//|| Redis
-		//||------------------------------------------------------------------------------------------------||
-
-		case "redis", "REDIS":
-			client, ctx, err := connectRedis(c)
-			if err != nil {
-				return nil, nil, nil, nil, fmt.Errorf("cache '%s' redis connect failed: %w", name, err)
-			}
-			fmt.Printf("\n[CACH] - Initializing cache: %s (backend: %s)", name, c.Backend)
-			redisMap[name] = &RedisCacheWrapper{
-				Name:   name,
-				Client: client,
-				Ctx:    ctx,
-			}
-
-		//||------------------------------------------------------------------------------------------------||
-		//|| KeyDB (redis protocol)
-		//||------------------------------------------------------------------------------------------------||
-
-		case "keydb", "KEYDB":
-			client, ctx, err := connectRedis(c)
-			if err != nil {
-				return nil, nil, nil, nil, fmt.Errorf("cache '%s' keydb connect failed: %w", name, err)
-			}
-			keydbMap[name] = &RedisCacheWrapper{
-				Name:   name,
-				Client: client,
-				Ctx:    ctx,
-			}
-
-		//||------------------------------------------------------------------------------------------------||
-		//|| Memcached
-		//||------------------------------------------------------------------------------------------------||
-
-		case "memcached", "MEMCACHED":
-			client, err := connectMemcached(c)
-			if err != nil {
-				return nil, nil, nil, nil, fmt.Errorf("cache '%s' memcached connect failed: %w", name, err)
-			}
-			memcMap[name] = &MemcachedCacheWrapper{
-				Name:   name,
-				Client: client,
-			}
-
-		//||------------------------------------------------------------------------------------------------||
-		//|| In-Memory
-		//||------------------------------------------------------------------------------------------------||
-
-		case "memory", "MEMORY":
-			memMap[name] = &MemoryCacheWrapper{
-				Name:  name,
-				Store: initMemoryStore(),
-			}
-
-		//||------------------------------------------------------------------------------------------------||
-		//|| Unsupported
-		//||------------------------------------------------------------------------------------------------||
+	names := make([]string, 0, len(cfg.Cache))
+	for name := range cfg.Cache {
+		names = append(names, name)
+	}
+	sort.Strings(names)
 
-		default:
-			return nil, nil, nil, nil, fmt.Errorf("unsupported cache backend: %s", c.Backend)
+	for _, name := range names {
+		if _, err := build(name); err != nil {
+			closeAll(caches)
+			return nil, err
 		}
 	}
 
 	//||------------------------------------------------------------------------------------------------||
-	//|| Return Maps
+	//|| Return Map
 	//||------------------------------------------------------------------------------------------------||
 
-	return redisMap, keydbMap, memcMap, memMap, nil
+	return caches, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| closeAll: release whatever was built before a failure
+//||------------------------------------------------------------------------------------------------||
+
+func closeAll(caches map[string]Cache) error {
+	var errs []error
+	for _, c := range caches {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
 }
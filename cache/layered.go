@@ -0,0 +1,387 @@
+//||------------------------------------------------------------------------------------------------||
+//|| Cache Package: Two-Tier (L1 memory + L2 Redis) Cache
+//|| layered.go
+//||------------------------------------------------------------------------------------------------||
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/ralphferrara/aria/base/random"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Layered: Defaults
+//||------------------------------------------------------------------------------------------------||
+
+const (
+	defaultLayeredL1TTL = 30 * time.Second
+	layeredStripes      = 256 // invalidation generations are tracked per stripe of keys
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Layered Cache Wrapper
+//||------------------------------------------------------------------------------------------------||
+
+type LayeredCacheWrapper struct {
+	Name    string
+	L1      *MemoryCacheWrapper
+	L2      LayeredL2
+	L1TTL   time.Duration
+	Channel string
+	nodeID  string
+	bus     invalidationBus
+	done    chan struct{}
+	stripes [layeredStripes]layeredStripe
+}
+
+// LayeredL2 is the shared tier (a RedisCacheWrapper in production).
+type LayeredL2 interface {
+	Cache
+	Tagger
+	Locker
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| layeredStripe: invalidation generation for the keys hashed to it
+//||
+//|| Every invalidation bumps gen under mu before touching L1; Get backfills L1 under the same
+//|| lock only if gen is unchanged since it started the L2 read, so a value read before a write
+//|| or delete is never put back into L1 after it.
+//||------------------------------------------------------------------------------------------------||
+
+type layeredStripe struct {
+	mu  sync.Mutex
+	gen uint64
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| invalidationBus: carries invalidation messages between nodes (Redis pub/sub)
+//||------------------------------------------------------------------------------------------------||
+
+type invalidationBus interface {
+	Publish(ctx context.Context, payload []byte) error
+	Channel() <-chan *redis.Message
+	Close() error
+}
+
+type redisBus struct {
+	client  redis.UniversalClient
+	channel string
+	pubsub  *redis.PubSub
+}
+
+func (b *redisBus) Publish(ctx context.Context, payload []byte) error {
+	return b.client.Publish(ctx, b.channel, payload).Err()
+}
+
+func (b *redisBus) Channel() <-chan *redis.Message { return b.pubsub.Channel() }
+
+func (b *redisBus) Close() error { return b.pubsub.Close() }
+
+//||------------------------------------------------------------------------------------------------||
+//|| Invalidation Message (published on every write/delete)
+//||------------------------------------------------------------------------------------------------||
+
+type layeredInvalidation struct {
+	Node string `json:"node"`
+	Key  string `json:"key,omitempty"`
+	Tag  string `json:"tag,omitempty"`
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| NewLayeredCache: compose L1 + L2 and subscribe to invalidations
+//||------------------------------------------------------------------------------------------------||
+
+func NewLayeredCache(name string, l1 *MemoryCacheWrapper, l2 *RedisCacheWrapper, l1TTL time.Duration, channel string) (*LayeredCacheWrapper, error) {
+	return newLayered(name, l1, l2, l1TTL, channel, true)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| newLayered: confirm=false skips waiting for the subscription (lazy mode; go-redis resubscribes)
+//||------------------------------------------------------------------------------------------------||
+
+func newLayered(name string, l1 *MemoryCacheWrapper, l2 *RedisCacheWrapper, l1TTL time.Duration, channel string, confirm bool) (*LayeredCacheWrapper, error) {
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Defaults
+	//||------------------------------------------------------------------------------------------------||
+
+	if l1 == nil || l2 == nil {
+		return nil, fmt.Errorf("layered cache '%s' requires both l1 and l2", name)
+	}
+	if channel == "" {
+		channel = "cache::invalidate::" + name
+	}
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Subscribe (wait for confirmation so no write is missed after return)
+	//||------------------------------------------------------------------------------------------------||
+
+	ctx := context.Background()
+	pubsub := l2.Client.Subscribe(ctx, channel)
+	if confirm {
+		if _, err := pubsub.Receive(ctx); err != nil {
+			_ = pubsub.Close()
+			return nil, fmt.Errorf("layered cache '%s' subscribe failed: %w", name, err)
+		}
+	}
+
+	return newLayeredWithBus(name, l1, l2, l1TTL, channel, &redisBus{client: l2.Client, channel: channel, pubsub: pubsub}), nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| newLayeredWithBus: compose L1 + any L2 over an already-subscribed bus
+//||------------------------------------------------------------------------------------------------||
+
+func newLayeredWithBus(name string, l1 *MemoryCacheWrapper, l2 LayeredL2, l1TTL time.Duration, channel string, bus invalidationBus) *LayeredCacheWrapper {
+	if l1TTL <= 0 {
+		l1TTL = defaultLayeredL1TTL
+	}
+	c := &LayeredCacheWrapper{
+		Name:    name,
+		L1:      l1,
+		L2:      l2,
+		L1TTL:   l1TTL,
+		Channel: channel,
+		nodeID:  random.UUIDString(),
+		bus:     bus,
+		done:    make(chan struct{}),
+	}
+	go c.listen()
+	return c
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Layered: Listen (drop L1 entries written or deleted by other nodes)
+//||------------------------------------------------------------------------------------------------||
+
+func (c *LayeredCacheWrapper) listen() {
+	defer close(c.done)
+	ctx := context.Background()
+	for msg := range c.bus.Channel() {
+		var inv layeredInvalidation
+		if err := json.Unmarshal([]byte(msg.Payload), &inv); err != nil || inv.Node == c.nodeID {
+			continue
+		}
+		if inv.Key != "" {
+			c.invalidate(inv.Key, func() { _ = c.L1.Delete(ctx, inv.Key) })
+		}
+		if inv.Tag != "" {
+			c.invalidateAll(func() { _ = c.L1.InvalidateTag(ctx, inv.Tag) })
+		}
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Layered: Publish Invalidation
+//||------------------------------------------------------------------------------------------------||
+
+func (c *LayeredCacheWrapper) publish(ctx context.Context, inv layeredInvalidation) error {
+	inv.Node = c.nodeID
+	payload, err := json.Marshal(inv)
+	if err != nil {
+		return err
+	}
+	return c.bus.Publish(ctx, payload)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Layered: Invalidate (bump the generation, then change L1, both under the stripe lock)
+//||------------------------------------------------------------------------------------------------||
+
+func (c *LayeredCacheWrapper) stripe(key string) *layeredStripe {
+	return &c.stripes[fnv1a(key)%layeredStripes]
+}
+
+func (c *LayeredCacheWrapper) invalidate(key string, update func()) {
+	s := c.stripe(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gen++
+	update()
+}
+
+// invalidateAll is invalidate for every key at once (tag invalidations name no keys).
+func (c *LayeredCacheWrapper) invalidateAll(update func()) {
+	for i := range c.stripes {
+		c.stripes[i].mu.Lock()
+		c.stripes[i].gen++
+	}
+	defer func() {
+		for i := range c.stripes {
+			c.stripes[i].mu.Unlock()
+		}
+	}()
+	update()
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Layered: Get (L1, then L2 with L1 backfill unless the key was invalidated meanwhile)
+//||------------------------------------------------------------------------------------------------||
+
+func (c *LayeredCacheWrapper) Get(ctx context.Context, key string) ([]byte, error) {
+	if val, err := c.L1.Get(ctx, key); err == nil {
+		return val, nil
+	}
+	s := c.stripe(key)
+	s.mu.Lock()
+	gen := s.gen
+	s.mu.Unlock()
+
+	val, err := c.L2.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	ttl := c.l1TTL(ttlOrZero(c.L2.TTL(ctx, key)))
+
+	s.mu.Lock()
+	if s.gen == gen {
+		_ = c.L1.Set(ctx, key, val, ttl)
+	}
+	s.mu.Unlock()
+	return val, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Layered: Set
+//||------------------------------------------------------------------------------------------------||
+
+func (c *LayeredCacheWrapper) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := c.L2.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+	c.invalidate(key, func() { _ = c.L1.Set(ctx, key, value, c.l1TTL(ttl)) })
+	return c.publish(ctx, layeredInvalidation{Key: key})
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Layered: Delete
+//||------------------------------------------------------------------------------------------------||
+
+func (c *LayeredCacheWrapper) Delete(ctx context.Context, key string) error {
+	err := c.L2.Delete(ctx, key)
+	c.invalidate(key, func() { _ = c.L1.Delete(ctx, key) })
+	if err != nil {
+		return err
+	}
+	return c.publish(ctx, layeredInvalidation{Key: key})
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Layered: Exists
+//||------------------------------------------------------------------------------------------------||
+
+func (c *LayeredCacheWrapper) Exists(ctx context.Context, key string) (bool, error) {
+	if ok, _ := c.L1.Exists(ctx, key); ok {
+		return true, nil
+	}
+	return c.L2.Exists(ctx, key)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Layered: TTL (authoritative value lives in L2)
+//||------------------------------------------------------------------------------------------------||
+
+func (c *LayeredCacheWrapper) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return c.L2.TTL(ctx, key)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Layered: SetWithTags
+//||------------------------------------------------------------------------------------------------||
+
+func (c *LayeredCacheWrapper) SetWithTags(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	if err := c.L2.SetWithTags(ctx, key, value, ttl, tags...); err != nil {
+		return err
+	}
+	c.invalidate(key, func() { _ = c.L1.SetWithTags(ctx, key, value, c.l1TTL(ttl), tags...) })
+	return c.publish(ctx, layeredInvalidation{Key: key})
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Layered: InvalidateTag
+//||------------------------------------------------------------------------------------------------||
+
+func (c *LayeredCacheWrapper) InvalidateTag(ctx context.Context, tag string) error {
+	err := c.L2.InvalidateTag(ctx, tag)
+	c.invalidateAll(func() { _ = c.L1.InvalidateTag(ctx, tag) })
+	if err != nil {
+		return err
+	}
+	return c.publish(ctx, layeredInvalidation{Tag: tag})
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Layered: Lock (always cluster-wide, so delegated to L2)
+//||------------------------------------------------------------------------------------------------||
+
+func (c *LayeredCacheWrapper) Lock(ctx context.Context, key string, ttl time.Duration) (*Lock, error) {
+	return c.L2.Lock(ctx, key, ttl)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Layered: Ping
+//||------------------------------------------------------------------------------------------------||
+
+func (c *LayeredCacheWrapper) Ping() error {
+	if c == nil || c.L2 == nil {
+		return fmt.Errorf("layered cache not initialized")
+	}
+	return c.L2.Ping()
+}
+
+func (c *LayeredCacheWrapper) PingContext(ctx context.Context) error {
+	if c == nil || c.L2 == nil {
+		return fmt.Errorf("layered cache not initialized")
+	}
+	return c.L2.PingContext(ctx)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Layered: Close (L2 is shared and closed by its own registry entry)
+//||------------------------------------------------------------------------------------------------||
+
+func (c *LayeredCacheWrapper) Close() error {
+	if c == nil || c.bus == nil {
+		return nil
+	}
+	err := c.bus.Close()
+	<-c.done
+	return errors.Join(err, c.L1.Close())
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Layered: L1 TTL (never outlive L2)
+//||------------------------------------------------------------------------------------------------||
+
+func (c *LayeredCacheWrapper) l1TTL(ttl time.Duration) time.Duration {
+	if ttl > 0 && ttl < c.L1TTL {
+		return ttl
+	}
+	return c.L1TTL
+}
+
+func ttlOrZero(ttl time.Duration, err error) time.Duration {
+	if err != nil {
+		return 0
+	}
+	return ttl
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Compile-time interface checks
+//||------------------------------------------------------------------------------------------------||
+
+var (
+	_ Cache  = (*LayeredCacheWrapper)(nil)
+	_ Tagger = (*LayeredCacheWrapper)(nil)
+	_ Locker = (*LayeredCacheWrapper)(nil)
+)
@@ -0,0 +1,278 @@
+//||------------------------------------------------------------------------------------------------||
+//|| Cache Package: Distributed Locks
+//|| lock.go
+//||------------------------------------------------------------------------------------------------||
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/ralphferrara/aria/base/random"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Lock: Errors
+//||------------------------------------------------------------------------------------------------||
+
+var (
+	ErrLockNotAcquired = errors.New("cache: lock not acquired")
+	ErrLockNotHeld     = errors.New("cache: lock not held")
+	ErrLockTTL         = errors.New("cache: lock ttl must be at least 1ms")
+)
+
+// checkLockTTL rejects TTLs a lease cannot have: Redis would never expire it (0) or drop it (< 1ms).
+func checkLockTTL(ttl time.Duration) error {
+	if ttl < time.Millisecond {
+		return fmt.Errorf("%w (got %s)", ErrLockTTL, ttl)
+	}
+	return nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Locker: implemented by backends that can hold a cluster-wide mutex
+//||------------------------------------------------------------------------------------------------||
+
+type Locker interface {
+	Lock(ctx context.Context, key string, ttl time.Duration) (*Lock, error)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| lockBackend: release/extend a lease by token
+//||------------------------------------------------------------------------------------------------||
+
+type lockBackend interface {
+	unlock(ctx context.Context, key, token string) error
+	extend(ctx context.Context, key, token string, ttl time.Duration) error
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Lock: a held lease (only the token holder may release or extend it)
+//||------------------------------------------------------------------------------------------------||
+
+type Lock struct {
+	Key     string
+	Token   string
+	backend lockBackend
+}
+
+func (l *Lock) Unlock(ctx context.Context) error {
+	return l.backend.unlock(ctx, l.Key, l.Token)
+}
+
+func (l *Lock) Extend(ctx context.Context, ttl time.Duration) error {
+	if err := checkLockTTL(ttl); err != nil {
+		return err
+	}
+	return l.backend.extend(ctx, l.Key, l.Token, ttl)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Lock Options (blocking acquire)
+//||------------------------------------------------------------------------------------------------||
+
+type lockOptions struct {
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+type LockOption func(*lockOptions)
+
+func WithLockBackoff(min, max time.Duration) LockOption {
+	return func(o *lockOptions) {
+		o.minBackoff = min
+		o.maxBackoff = max
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| AcquireLock: retry Lock with jittered exponential backoff until ctx is done
+//||------------------------------------------------------------------------------------------------||
+
+func AcquireLock(ctx context.Context, l Locker, key string, ttl time.Duration, opts ...LockOption) (*Lock, error) {
+	o := lockOptions{minBackoff: 10 * time.Millisecond, maxBackoff: time.Second}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if err := checkLockTTL(ttl); err != nil {
+		return nil, err
+	}
+
+	backoff := o.minBackoff
+	if backoff <= 0 {
+		backoff = time.Millisecond
+	}
+	for {
+		lock, err := l.Lock(ctx, key, ttl)
+		if err == nil {
+			return lock, nil
+		}
+		if !errors.Is(err, ErrLockNotAcquired) {
+			return nil, err
+		}
+
+		wait := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, errors.Join(ErrLockNotAcquired, ctx.Err())
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if backoff > o.maxBackoff {
+			backoff = o.maxBackoff
+		}
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Lock Key
+//||------------------------------------------------------------------------------------------------||
+
+func lockKey(key string) string {
+	return "lock::" + key
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Redis/KeyDB: Lua scripts (compare token, then act)
+//||------------------------------------------------------------------------------------------------||
+
+var (
+	redisUnlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+	redisExtendScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Redis/KeyDB: Lock (SET NX PX, single attempt)
+//||------------------------------------------------------------------------------------------------||
+
+func (c *RedisCacheWrapper) Lock(ctx context.Context, key string, ttl time.Duration) (*Lock, error) {
+	if err := checkLockTTL(ttl); err != nil {
+		return nil, err
+	}
+	token := random.UUIDString()
+	ok, err := c.Client.SetNX(ctx, lockKey(key), token, ttl).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrLockNotAcquired
+	}
+	return &Lock{Key: key, Token: token, backend: c}, nil
+}
+
+func (c *RedisCacheWrapper) unlock(ctx context.Context, key, token string) error {
+	n, err := redisUnlockScript.Run(ctx, c.Client, []string{lockKey(key)}, token).Int64()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+func (c *RedisCacheWrapper) extend(ctx context.Context, key, token string, ttl time.Duration) error {
+	n, err := redisExtendScript.Run(ctx, c.Client, []string{lockKey(key)}, token, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Memory: Lock (process-local fallback for tests and single-node setups)
+//||
+//|| Leases live in their own map, not the shards, so max_entries/max_bytes eviction can never
+//|| drop a lock that is still held; only the TTL ends one.
+//||------------------------------------------------------------------------------------------------||
+
+type memoryLease struct {
+	token   string
+	expires time.Time
+}
+
+func (l memoryLease) live(now time.Time) bool {
+	return now.Before(l.expires)
+}
+
+func (c *MemoryCacheWrapper) Lock(ctx context.Context, key string, ttl time.Duration) (*Lock, error) {
+	if err := checkLockTTL(ttl); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	c.lockMu.Lock()
+	defer c.lockMu.Unlock()
+	if lease, held := c.locks[key]; held && lease.live(now) {
+		return nil, ErrLockNotAcquired
+	}
+	lease := memoryLease{token: random.UUIDString(), expires: now.Add(ttl)}
+	if c.locks == nil {
+		c.locks = make(map[string]memoryLease)
+	}
+	c.locks[key] = lease
+	return &Lock{Key: key, Token: lease.token, backend: c}, nil
+}
+
+func (c *MemoryCacheWrapper) unlock(ctx context.Context, key, token string) error {
+	c.lockMu.Lock()
+	defer c.lockMu.Unlock()
+	lease, held := c.locks[key]
+	if !held || !lease.live(time.Now()) || lease.token != token {
+		return ErrLockNotHeld
+	}
+	delete(c.locks, key)
+	return nil
+}
+
+func (c *MemoryCacheWrapper) extend(ctx context.Context, key, token string, ttl time.Duration) error {
+	now := time.Now()
+	c.lockMu.Lock()
+	defer c.lockMu.Unlock()
+	lease, held := c.locks[key]
+	if !held || !lease.live(now) || lease.token != token {
+		return ErrLockNotHeld
+	}
+	lease.expires = now.Add(ttl)
+	c.locks[key] = lease
+	return nil
+}
+
+// sweepLocks drops expired leases (called by the sweeper).
+func (c *MemoryCacheWrapper) sweepLocks(now time.Time) {
+	c.lockMu.Lock()
+	defer c.lockMu.Unlock()
+	for key, lease := range c.locks {
+		if !lease.live(now) {
+			delete(c.locks, key)
+		}
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Compile-time interface checks
+//||------------------------------------------------------------------------------------------------||
+
+var (
+	_ Locker = (*RedisCacheWrapper)(nil)
+	_ Locker = (*MemoryCacheWrapper)(nil)
+)
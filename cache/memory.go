@@ -0,0 +1,385 @@
+//||------------------------------------------------------------------------------------------------||
+//|| Cache Package: In-Memory Backend
+//|| memory.go
+//||------------------------------------------------------------------------------------------------||
+
+package cache
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ralphferrara/aria/config"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Memory: Eviction Policies
+//||------------------------------------------------------------------------------------------------||
+
+const (
+	EvictionLRU = "lru"
+	EvictionLFU = "lfu"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Memory: Defaults
+//||------------------------------------------------------------------------------------------------||
+
+const (
+	defaultMemoryShards = 16
+	defaultMemorySweep  = 60 * time.Second
+)
+
+var errMemoryUninitialized = errors.New("memory cache not initialized: build it with NewMemoryCache")
+
+//||------------------------------------------------------------------------------------------------||
+//|| Memory Shard (one lock per shard, LRU list front = most recent)
+//||
+//|| LFU shards also keep freqs: buckets of equal hit count, lowest first, each ordered most
+//|| recent first, so the victim is found in O(1) instead of scanning every entry.
+//||------------------------------------------------------------------------------------------------||
+
+type memoryShard struct {
+	mu         sync.Mutex
+	items      map[string]*list.Element
+	order      *list.List
+	freqs      *list.List // of *lfuBucket (LFU only)
+	bytes      int64
+	maxEntries int
+	maxBytes   int64
+	policy     string
+}
+
+type lfuBucket struct {
+	hits    uint64
+	entries *list.List // of *memoryEntry
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| NewMemoryCache: Build a sharded in-process cache from config
+//||------------------------------------------------------------------------------------------------||
+
+func NewMemoryCache(name string, cfg config.CacheInstanceConfig) (*MemoryCacheWrapper, error) {
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Policy
+	//||------------------------------------------------------------------------------------------------||
+
+	policy := strings.ToLower(cfg.Eviction)
+	switch policy {
+	case "":
+		policy = EvictionLRU
+	case EvictionLRU, EvictionLFU:
+		// ok
+	default:
+		return nil, fmt.Errorf("unsupported memory eviction policy: %s", cfg.Eviction)
+	}
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Shards (limits are split evenly, rounded up)
+	//||------------------------------------------------------------------------------------------------||
+
+	count := cfg.Shards
+	if count <= 0 {
+		count = defaultMemoryShards
+	}
+	shards := make([]*memoryShard, count)
+	for i := range shards {
+		shards[i] = &memoryShard{
+			items:      make(map[string]*list.Element),
+			order:      list.New(),
+			freqs:      list.New(),
+			maxEntries: ceilDiv(cfg.MaxEntries, count),
+			maxBytes:   int64(ceilDiv(int(cfg.MaxBytes), count)),
+			policy:     policy,
+		}
+	}
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Wrapper + Sweeper
+	//||------------------------------------------------------------------------------------------------||
+
+	sweep := time.Duration(cfg.SweepInterval) * time.Second
+	if sweep <= 0 {
+		sweep = defaultMemorySweep
+	}
+	c := &MemoryCacheWrapper{
+		Name:   name,
+		shards: shards,
+		tags:   make(map[string]map[string]struct{}),
+		stop:   make(chan struct{}),
+	}
+	go c.sweeper(sweep)
+	return c, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Memory: Shard For Key (FNV-1a)
+//||------------------------------------------------------------------------------------------------||
+
+func (c *MemoryCacheWrapper) shard(key string) (*memoryShard, error) {
+	if c == nil || len(c.shards) == 0 {
+		return nil, errMemoryUninitialized
+	}
+	return c.shards[fnv1a(key)%uint32(len(c.shards))], nil
+}
+
+func fnv1a(key string) uint32 {
+	var h uint32 = 2166136261
+	for i := 0; i < len(key); i++ {
+		h ^= uint32(key[i])
+		h *= 16777619
+	}
+	return h
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Memory: Sweeper (drops expired entries and stale tag members until Close)
+//||------------------------------------------------------------------------------------------------||
+
+func (c *MemoryCacheWrapper) sweeper(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case now := <-ticker.C:
+			for _, s := range c.shards {
+				s.sweep(now)
+			}
+			c.pruneTags(context.Background())
+			c.sweepLocks(now)
+		}
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Shard: Get (bumps recency + hit count)
+//||------------------------------------------------------------------------------------------------||
+
+func (s *memoryShard) get(key string, now time.Time) (*memoryEntry, bool) {
+	el, ok := s.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*memoryEntry)
+	if entry.expired(now) {
+		s.remove(el)
+		return nil, false
+	}
+	entry.Hits++
+	s.order.MoveToFront(el)
+	if s.policy == EvictionLFU {
+		s.lfuPromote(entry)
+	}
+	return entry, true
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Shard: Peek (no recency or hit change)
+//||------------------------------------------------------------------------------------------------||
+
+func (s *memoryShard) peek(key string, now time.Time) (*memoryEntry, bool) {
+	el, ok := s.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*memoryEntry)
+	if entry.expired(now) {
+		s.remove(el)
+		return nil, false
+	}
+	return entry, true
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Shard: Set (insert or replace, then evict down to limits)
+//||------------------------------------------------------------------------------------------------||
+
+func (s *memoryShard) set(entry *memoryEntry) {
+	if el, ok := s.items[entry.Key]; ok {
+		old := el.Value.(*memoryEntry)
+		entry.Hits = old.Hits
+		s.bytes += entry.size() - old.size()
+		el.Value = entry
+		s.order.MoveToFront(el)
+		if s.policy == EvictionLFU {
+			entry.bucket, entry.node = old.bucket, old.node
+			entry.node.Value = entry
+			entry.bucket.Value.(*lfuBucket).entries.MoveToFront(entry.node)
+		}
+	} else {
+		s.items[entry.Key] = s.order.PushFront(entry)
+		s.bytes += entry.size()
+		if s.policy == EvictionLFU {
+			s.lfuAdd(entry, nil)
+		}
+	}
+	s.evict(entry.Key)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Shard: Delete
+//||------------------------------------------------------------------------------------------------||
+
+func (s *memoryShard) delete(key string) {
+	if el, ok := s.items[key]; ok {
+		s.remove(el)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Shard: Remove Element
+//||------------------------------------------------------------------------------------------------||
+
+func (s *memoryShard) remove(el *list.Element) {
+	entry := el.Value.(*memoryEntry)
+	s.order.Remove(el)
+	delete(s.items, entry.Key)
+	s.bytes -= entry.size()
+	if s.policy == EvictionLFU {
+		s.lfuRemove(entry)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Shard: Evict (never evicts the key just written unless it alone exceeds the limit)
+//||------------------------------------------------------------------------------------------------||
+
+func (s *memoryShard) evict(keep string) {
+	for s.overLimit() {
+		victim := s.victim(keep)
+		if victim == nil {
+			victim = s.items[keep]
+		}
+		if victim == nil {
+			return
+		}
+		s.remove(victim)
+	}
+}
+
+func (s *memoryShard) overLimit() bool {
+	if s.maxEntries > 0 && len(s.items) > s.maxEntries {
+		return true
+	}
+	return s.maxBytes > 0 && s.bytes > s.maxBytes
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Shard: Victim (LRU = back of list, LFU = fewest hits, oldest on ties)
+//||
+//|| Only keep is ever skipped, so both walks stop after at most two entries.
+//||------------------------------------------------------------------------------------------------||
+
+func (s *memoryShard) victim(keep string) *list.Element {
+	if s.policy == EvictionLFU {
+		for b := s.freqs.Front(); b != nil; b = b.Next() {
+			for n := b.Value.(*lfuBucket).entries.Back(); n != nil; n = n.Prev() {
+				if entry := n.Value.(*memoryEntry); entry.Key != keep {
+					return s.items[entry.Key]
+				}
+			}
+		}
+		return nil
+	}
+	for el := s.order.Back(); el != nil; el = el.Prev() {
+		if el.Value.(*memoryEntry).Key != keep {
+			return el
+		}
+	}
+	return nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Shard: LFU Buckets
+//||------------------------------------------------------------------------------------------------||
+
+// lfuAdd files entry under entry.Hits, looking in the bucket after prev (nil = the lowest).
+func (s *memoryShard) lfuAdd(entry *memoryEntry, prev *list.Element) {
+	next := s.freqs.Front()
+	if prev != nil {
+		next = prev.Next()
+	}
+	b := next
+	if b == nil || b.Value.(*lfuBucket).hits != entry.Hits {
+		bucket := &lfuBucket{hits: entry.Hits, entries: list.New()}
+		if prev != nil {
+			b = s.freqs.InsertAfter(bucket, prev)
+		} else {
+			b = s.freqs.PushFront(bucket)
+		}
+	}
+	entry.bucket = b
+	entry.node = b.Value.(*lfuBucket).entries.PushFront(entry)
+}
+
+// lfuPromote moves entry up to the bucket for its new hit count.
+func (s *memoryShard) lfuPromote(entry *memoryEntry) {
+	from := entry.bucket
+	from.Value.(*lfuBucket).entries.Remove(entry.node)
+	s.lfuAdd(entry, from)
+	if from.Value.(*lfuBucket).entries.Len() == 0 {
+		s.freqs.Remove(from)
+	}
+}
+
+func (s *memoryShard) lfuRemove(entry *memoryEntry) {
+	bucket := entry.bucket.Value.(*lfuBucket)
+	bucket.entries.Remove(entry.node)
+	if bucket.entries.Len() == 0 {
+		s.freqs.Remove(entry.bucket)
+	}
+	entry.bucket, entry.node = nil, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Shard: Sweep Expired
+//||------------------------------------------------------------------------------------------------||
+
+func (s *memoryShard) sweep(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for el := s.order.Back(); el != nil; {
+		prev := el.Prev()
+		if el.Value.(*memoryEntry).expired(now) {
+			s.remove(el)
+		}
+		el = prev
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Memory: Len (live + not-yet-swept entries)
+//||------------------------------------------------------------------------------------------------||
+
+func (c *MemoryCacheWrapper) Len() int {
+	n := 0
+	for _, s := range c.shards {
+		s.mu.Lock()
+		n += len(s.items)
+		s.mu.Unlock()
+	}
+	return n
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Helpers
+//||------------------------------------------------------------------------------------------------||
+
+func (e *memoryEntry) size() int64 {
+	return int64(len(e.Key) + len(e.Value))
+}
+
+func ceilDiv(total, parts int) int {
+	if total <= 0 {
+		return 0
+	}
+	return (total + parts - 1) / parts
+}
@@ -1,20 +1,30 @@
 package cache
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 //||------------------------------------------------------------------------------------------------||
 //|| Redis/KeyDB: Ping
 //||------------------------------------------------------------------------------------------------||
 
 func (c *RedisCacheWrapper) Ping() error {
+	if c == nil {
+		return fmt.Errorf("redis client not initialized")
+	}
+	return c.PingContext(c.Ctx)
+}
+
+func (c *RedisCacheWrapper) PingContext(ctx context.Context) error {
 	if c == nil || c.Client == nil {
 		return fmt.Errorf("redis client not initialized")
 	}
-	return c.Client.Ping(c.Ctx).Err()
+	return c.Client.Ping(ctx).Err()
 }
 
 //||------------------------------------------------------------------------------------------------||
-//|| Memcached: Ping
+//|| Memcached: Ping (the client has no context, so PingContext stops waiting at the deadline)
 //||------------------------------------------------------------------------------------------------||
 
 func (c *MemcachedCacheWrapper) Ping() error {
@@ -24,13 +34,28 @@ func (c *MemcachedCacheWrapper) Ping() error {
 	return c.Client.Ping()
 }
 
+func (c *MemcachedCacheWrapper) PingContext(ctx context.Context) error {
+	errc := make(chan error, 1)
+	go func() { errc <- c.Ping() }()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 //||------------------------------------------------------------------------------------------------||
 //|| Memory: Ping (always ok)
 //||------------------------------------------------------------------------------------------------||
 
 func (c *MemoryCacheWrapper) Ping() error {
-	if c == nil {
-		return fmt.Errorf("memory cache not initialized")
+	if c == nil || len(c.shards) == 0 {
+		return errMemoryUninitialized
 	}
 	return nil
 }
+
+func (c *MemoryCacheWrapper) PingContext(ctx context.Context) error {
+	return c.Ping()
+}
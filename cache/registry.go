@@ -0,0 +1,146 @@
+//||------------------------------------------------------------------------------------------------||
+//|| Cache Package: Backend Registry
+//|| registry.go
+//||------------------------------------------------------------------------------------------------||
+
+package cache
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ralphferrara/aria/config"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Factory: builds one named cache; deps resolves other caches by name (built on demand)
+//||------------------------------------------------------------------------------------------------||
+
+type Resolver func(name string) (Cache, error)
+
+type Factory func(name string, cfg config.CacheInstanceConfig, deps Resolver) (Cache, error)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Registry
+//||------------------------------------------------------------------------------------------------||
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{}
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Register: add (or replace) a backend; names are case-insensitive
+//||------------------------------------------------------------------------------------------------||
+
+func Register(backend string, factory Factory) {
+	if backend == "" || factory == nil {
+		panic("cache: Register requires a backend name and factory")
+	}
+	registryMu.Lock()
+	registry[strings.ToLower(backend)] = factory
+	registryMu.Unlock()
+	config.RegisterBackend(config.SectionCache, backend)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Backends: registered backend names (sorted)
+//||------------------------------------------------------------------------------------------------||
+
+func Backends() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	out := make([]string, 0, len(registry))
+	for name := range registry {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| lookup
+//||------------------------------------------------------------------------------------------------||
+
+func lookup(backend string) (Factory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	f, ok := registry[strings.ToLower(backend)]
+	return f, ok
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Built-in Backends
+//||------------------------------------------------------------------------------------------------||
+
+func init() {
+	Register("redis", newRedisCache)
+	Register("keydb", newRedisCache)
+	Register("memcached", newMemcachedCache)
+	Register("memory", newMemoryCache)
+	Register("layered", newLayeredCache)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Redis / KeyDB (redis protocol)
+//||------------------------------------------------------------------------------------------------||
+
+func newRedisCache(name string, cfg config.CacheInstanceConfig, _ Resolver) (Cache, error) {
+	client, ctx, err := connectRedis(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%s connect failed: %w", cfg.Backend, err)
+	}
+	return &RedisCacheWrapper{Name: name, Client: client, Ctx: ctx}, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Memcached
+//||------------------------------------------------------------------------------------------------||
+
+func newMemcachedCache(name string, cfg config.CacheInstanceConfig, _ Resolver) (Cache, error) {
+	client, err := connectMemcached(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("memcached connect failed: %w", err)
+	}
+	return &MemcachedCacheWrapper{Name: name, Client: client}, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| In-Memory
+//||------------------------------------------------------------------------------------------------||
+
+func newMemoryCache(name string, cfg config.CacheInstanceConfig, _ Resolver) (Cache, error) {
+	mem, err := NewMemoryCache(name, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("memory init failed: %w", err)
+	}
+	return mem, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Layered (L1 memory + named redis/keydb L2)
+//||------------------------------------------------------------------------------------------------||
+
+func newLayeredCache(name string, cfg config.CacheInstanceConfig, deps Resolver) (Cache, error) {
+	dep, err := deps(cfg.L2)
+	if err != nil {
+		return nil, fmt.Errorf("layered l2: %w", err)
+	}
+	l2, ok := dep.(*RedisCacheWrapper)
+	if !ok {
+		return nil, fmt.Errorf("layered l2 '%s' is not a redis/keydb cache", cfg.L2)
+	}
+	l1, err := NewMemoryCache(name, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("layered l1 init failed: %w", err)
+	}
+	layered, err := newLayered(name, l1, l2, time.Duration(cfg.L1TTL)*time.Second, cfg.Channel, !cfg.Lazy)
+	if err != nil {
+		_ = l1.Close()
+		return nil, err
+	}
+	return layered, nil
+}
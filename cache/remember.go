@@ -0,0 +1,220 @@
+//||------------------------------------------------------------------------------------------------||
+//|| Cache Package: Read-Through Helpers
+//|| remember.go
+//||------------------------------------------------------------------------------------------------||
+
+package cache
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Loader: computes a value on cache miss
+//||------------------------------------------------------------------------------------------------||
+
+type Loader func(ctx context.Context) ([]byte, error)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Remember Options
+//||------------------------------------------------------------------------------------------------||
+
+type rememberOptions struct {
+	stale time.Duration
+}
+
+type RememberOption func(*rememberOptions)
+
+//||------------------------------------------------------------------------------------------------||
+//|| WithStale: serve an expired value for up to d while one caller refreshes it
+//|| Values written this way carry a freshness header, so every Remember call on
+//|| the same key must use the option consistently.
+//||------------------------------------------------------------------------------------------------||
+
+func WithStale(d time.Duration) RememberOption {
+	return func(o *rememberOptions) {
+		o.stale = d
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Remember: Get, or load + Set with concurrent loaders de-duplicated per key
+//||------------------------------------------------------------------------------------------------||
+
+func Remember(ctx context.Context, c Cache, key string, ttl time.Duration, loader Loader, opts ...RememberOption) ([]byte, error) {
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Options
+	//||------------------------------------------------------------------------------------------------||
+
+	o := rememberOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Cache Hit
+	//||------------------------------------------------------------------------------------------------||
+
+	raw, err := c.Get(ctx, key)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		if o.stale <= 0 {
+			return raw, nil
+		}
+		fresh, value, ok := decodeStale(raw)
+		if ok {
+			if time.Now().After(fresh) {
+				go refresh(context.WithoutCancel(ctx), c, key, ttl, loader, o)
+			}
+			return value, nil
+		}
+	}
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Cache Miss: one loader per key, everyone else waits for its result
+	//||------------------------------------------------------------------------------------------------||
+
+	return refresh(ctx, c, key, ttl, loader, o)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| RememberJSON: typed Remember, values are stored as JSON
+//||------------------------------------------------------------------------------------------------||
+
+func RememberJSON[T any](ctx context.Context, c Cache, key string, ttl time.Duration, loader func(ctx context.Context) (T, error), opts ...RememberOption) (T, error) {
+	var out T
+	raw, err := Remember(ctx, c, key, ttl, func(ctx context.Context) ([]byte, error) {
+		v, err := loader(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(v)
+	}, opts...)
+	if err != nil {
+		return out, err
+	}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return out, fmt.Errorf("cache '%s' decode failed: %w", key, err)
+	}
+	return out, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| refresh: run the loader once per cache+key and store the result
+//||
+//|| The flight is shared, so it runs detached from the first caller's cancellation; each caller
+//|| still stops waiting when its own ctx is done.
+//||------------------------------------------------------------------------------------------------||
+
+func refresh(ctx context.Context, c Cache, key string, ttl time.Duration, loader Loader, o rememberOptions) ([]byte, error) {
+	flightCtx := context.WithoutCancel(ctx)
+	return flights.do(ctx, fmt.Sprintf("%p|%s", c, key), func() ([]byte, error) {
+		value, err := loader(flightCtx)
+		if err != nil {
+			return nil, err
+		}
+		if o.stale > 0 {
+			physical := ttl + o.stale
+			if ttl <= 0 {
+				physical = 0
+			}
+			err = c.Set(flightCtx, key, encodeStale(time.Now().Add(ttl), value, ttl <= 0), physical)
+		} else {
+			err = c.Set(flightCtx, key, value, ttl)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return value, nil
+	})
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Stale Envelope: [magic][fresh-until unix nanos][value]
+//||------------------------------------------------------------------------------------------------||
+
+var staleMagic = []byte{0xa7, 0x1a, 0x53, 0x57}
+
+const staleHeader = 12
+
+func encodeStale(fresh time.Time, value []byte, forever bool) []byte {
+	out := make([]byte, staleHeader+len(value))
+	copy(out, staleMagic)
+	nanos := fresh.UnixNano()
+	if forever {
+		nanos = 1<<63 - 1
+	}
+	binary.BigEndian.PutUint64(out[4:staleHeader], uint64(nanos))
+	copy(out[staleHeader:], value)
+	return out
+}
+
+func decodeStale(raw []byte) (time.Time, []byte, bool) {
+	if len(raw) < staleHeader || string(raw[:4]) != string(staleMagic) {
+		return time.Time{}, nil, false
+	}
+	nanos := int64(binary.BigEndian.Uint64(raw[4:staleHeader]))
+	return time.Unix(0, nanos), raw[staleHeader:], true
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Flight Group (singleflight-style de-duplication)
+//||------------------------------------------------------------------------------------------------||
+
+var ErrLoaderPanic = errors.New("cache: loader panicked")
+
+type flightCall struct {
+	done chan struct{}
+	val  []byte
+	err  error
+}
+
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+var flights = &flightGroup{calls: make(map[string]*flightCall)}
+
+// do starts fn for key unless it is already running, then waits for it or for ctx.
+func (g *flightGroup) do(ctx context.Context, key string, fn func() ([]byte, error)) ([]byte, error) {
+	g.mu.Lock()
+	call, ok := g.calls[key]
+	if !ok {
+		call = &flightCall{done: make(chan struct{})}
+		g.calls[key] = call
+		go g.run(key, call, fn)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.val, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// run executes fn; a panic reaches every waiter as an error wrapping ErrLoaderPanic.
+func (g *flightGroup) run(key string, call *flightCall, fn func() ([]byte, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			call.val, call.err = nil, fmt.Errorf("%w: %v\n%s", ErrLoaderPanic, r, debug.Stack())
+		}
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(call.done)
+	}()
+	call.val, call.err = fn()
+}
@@ -0,0 +1,164 @@
+//||------------------------------------------------------------------------------------------------||
+//|| Cache Package: Tag-Based Invalidation
+//|| tags.go
+//||------------------------------------------------------------------------------------------------||
+
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Tagger: implemented by backends that can invalidate keys as a group
+//||------------------------------------------------------------------------------------------------||
+
+type Tagger interface {
+	SetWithTags(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error
+	InvalidateTag(ctx context.Context, tag string) error
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Tag Key
+//||------------------------------------------------------------------------------------------------||
+
+func tagKey(tag string) string {
+	return "tag::" + tag
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Redis/KeyDB: add member and keep the tag set alive at least as long as it
+//||------------------------------------------------------------------------------------------------||
+
+var redisTagScript = redis.NewScript(`
+local existed = redis.call("EXISTS", KEYS[1])
+local current = redis.call("PTTL", KEYS[1])
+redis.call("SADD", KEYS[1], ARGV[1])
+local ttl = tonumber(ARGV[2])
+if ttl <= 0 then
+	redis.call("PERSIST", KEYS[1])
+elseif existed == 0 or (current >= 0 and current < ttl) then
+	redis.call("PEXPIRE", KEYS[1], ttl)
+end
+return 1`)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Redis/KeyDB: SetWithTags
+//||------------------------------------------------------------------------------------------------||
+
+func (c *RedisCacheWrapper) SetWithTags(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	if err := c.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		if err := redisTagScript.Run(ctx, c.Client, []string{tagKey(tag)}, key, tagTTL(ttl)).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tagTTL is the tag set lifetime in ms; sub-ms TTLs round up so they never read as "no expiry".
+func tagTTL(ttl time.Duration) int64 {
+	ms := ttl.Milliseconds()
+	if ttl > 0 && ms == 0 {
+		ms = 1
+	}
+	return ms
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Redis/KeyDB: InvalidateTag (one DEL per key so cluster slots never cross)
+//||
+//|| Only the members read are removed (SREM before DEL), so a key tagged by a concurrent
+//|| SetWithTags stays in the set; Redis drops the set itself once it is empty.
+//||------------------------------------------------------------------------------------------------||
+
+func (c *RedisCacheWrapper) InvalidateTag(ctx context.Context, tag string) error {
+	keys, err := c.Client.SMembers(ctx, tagKey(tag)).Result()
+	if err != nil || len(keys) == 0 {
+		return err
+	}
+	members := make([]interface{}, len(keys))
+	for i, key := range keys {
+		members[i] = key
+	}
+	if err := c.Client.SRem(ctx, tagKey(tag), members...).Err(); err != nil {
+		return err
+	}
+	_, err = c.Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.Del(ctx, key)
+		}
+		return nil
+	})
+	return err
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Memory: SetWithTags
+//||------------------------------------------------------------------------------------------------||
+
+func (c *MemoryCacheWrapper) SetWithTags(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	if err := c.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+	c.tagMu.Lock()
+	defer c.tagMu.Unlock()
+	for _, tag := range tags {
+		members, ok := c.tags[tag]
+		if !ok {
+			members = make(map[string]struct{})
+			c.tags[tag] = members
+		}
+		members[key] = struct{}{}
+	}
+	return nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Memory: InvalidateTag
+//||------------------------------------------------------------------------------------------------||
+
+func (c *MemoryCacheWrapper) InvalidateTag(ctx context.Context, tag string) error {
+	c.tagMu.Lock()
+	members := c.tags[tag]
+	delete(c.tags, tag)
+	c.tagMu.Unlock()
+
+	for key := range members {
+		_ = c.Delete(ctx, key)
+	}
+	return nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Memory: Prune Tag Index (drop members that expired or were evicted)
+//||------------------------------------------------------------------------------------------------||
+
+func (c *MemoryCacheWrapper) pruneTags(ctx context.Context) {
+	c.tagMu.Lock()
+	defer c.tagMu.Unlock()
+	for tag, members := range c.tags {
+		for key := range members {
+			if ok, _ := c.Exists(ctx, key); !ok {
+				delete(members, key)
+			}
+		}
+		if len(members) == 0 {
+			delete(c.tags, tag)
+		}
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Compile-time interface checks
+//||------------------------------------------------------------------------------------------------||
+
+var (
+	_ Tagger = (*RedisCacheWrapper)(nil)
+	_ Tagger = (*MemoryCacheWrapper)(nil)
+)
@@ -6,7 +6,11 @@
 package cache
 
 import (
+	"container/list"
 	"context"
+	"errors"
+	"sync"
+	"time"
 
 	"github.com/bradfitz/gomemcache/memcache"
 	"github.com/go-redis/redis/v8"
@@ -25,13 +29,37 @@ const (
 	BackendMemory    CacheBackend = "MEMORY"
 )
 
+//||------------------------------------------------------------------------------------------------||
+//|| Cache: Errors
+//||------------------------------------------------------------------------------------------------||
+
+var (
+	ErrNotFound    = errors.New("cache: key not found")
+	ErrUnsupported = errors.New("cache: operation not supported by backend")
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Cache: Interface implemented by every backend
+//||------------------------------------------------------------------------------------------------||
+
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+	Exists(ctx context.Context, key string) (bool, error)
+	TTL(ctx context.Context, key string) (time.Duration, error)
+	Ping() error
+	PingContext(ctx context.Context) error
+	Close() error
+}
+
 //||------------------------------------------------------------------------------------------------||
 //|| Redis/KeyDB Cache Wrapper
 //||------------------------------------------------------------------------------------------------||
 
 type RedisCacheWrapper struct {
 	Name   string
-	Client *redis.Client
+	Client redis.UniversalClient
 	Ctx    context.Context
 }
 
@@ -45,10 +73,39 @@ type MemcachedCacheWrapper struct {
 }
 
 //||------------------------------------------------------------------------------------------------||
-//|| Memory Cache Wrapper
+//|| Memory Cache Wrapper (build it with NewMemoryCache; a zero value fails every call)
 //||------------------------------------------------------------------------------------------------||
 
 type MemoryCacheWrapper struct {
-	Name  string
-	Store map[string]interface{}
+	Name      string
+	shards    []*memoryShard
+	tagMu     sync.Mutex
+	tags      map[string]map[string]struct{}
+	lockMu    sync.Mutex
+	locks     map[string]memoryLease // held leases, never evicted
+	stop      chan struct{}
+	closeOnce sync.Once
 }
+
+//||------------------------------------------------------------------------------------------------||
+//|| Memory Cache Entry
+//||------------------------------------------------------------------------------------------------||
+
+type memoryEntry struct {
+	Key     string
+	Value   []byte
+	Expires time.Time
+	Hits    uint64
+	bucket  *list.Element // LFU only: the shard's frequency bucket holding this entry
+	node    *list.Element // LFU only: this entry inside that bucket
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Compile-time interface checks
+//||------------------------------------------------------------------------------------------------||
+
+var (
+	_ Cache = (*RedisCacheWrapper)(nil)
+	_ Cache = (*MemcachedCacheWrapper)(nil)
+	_ Cache = (*MemoryCacheWrapper)(nil)
+)
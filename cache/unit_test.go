@@ -0,0 +1,949 @@
+//||------------------------------------------------------------------------------------------------||
+//|| Cache Package: Unit Tests
+//|| unit_test.go
+//||------------------------------------------------------------------------------------------------||
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/ralphferrara/aria/config"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| helper: build a memory cache and close it on cleanup
+//||------------------------------------------------------------------------------------------------||
+
+func newTestMemory(t *testing.T, cfg config.CacheInstanceConfig) *MemoryCacheWrapper {
+	t.Helper()
+	cfg.Backend = "memory"
+	c, err := NewMemoryCache("test", cfg)
+	if err != nil {
+		t.Fatalf("NewMemoryCache: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Memory Get/Set/Delete/Exists
+//||------------------------------------------------------------------------------------------------||
+
+func TestMemory_Basic(t *testing.T) {
+	ctx := context.Background()
+	c := newTestMemory(t, config.CacheInstanceConfig{})
+
+	if _, err := c.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get(missing) err = %v, want ErrNotFound", err)
+	}
+	if err := c.Set(ctx, "k", []byte("v"), 0); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	got, err := c.Get(ctx, "k")
+	if err != nil || string(got) != "v" {
+		t.Fatalf("Get = %q, %v", got, err)
+	}
+	if ok, _ := c.Exists(ctx, "k"); !ok {
+		t.Fatal("Exists(k) = false")
+	}
+	if ttl, err := c.TTL(ctx, "k"); err != nil || ttl != 0 {
+		t.Fatalf("TTL = %v, %v; want 0, nil", ttl, err)
+	}
+	_ = c.Delete(ctx, "k")
+	if ok, _ := c.Exists(ctx, "k"); ok {
+		t.Fatal("Exists(k) after Delete = true")
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Memory TTL Expiry + Sweeper
+//||------------------------------------------------------------------------------------------------||
+
+func TestMemory_TTLExpiry(t *testing.T) {
+	ctx := context.Background()
+	c := newTestMemory(t, config.CacheInstanceConfig{SweepInterval: 1})
+
+	_ = c.Set(ctx, "short", []byte("x"), 50*time.Millisecond)
+	if ttl, err := c.TTL(ctx, "short"); err != nil || ttl <= 0 {
+		t.Fatalf("TTL = %v, %v", ttl, err)
+	}
+	time.Sleep(80 * time.Millisecond)
+	if _, err := c.Get(ctx, "short"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get after expiry err = %v", err)
+	}
+
+	_ = c.Set(ctx, "swept", []byte("x"), 50*time.Millisecond)
+	time.Sleep(1200 * time.Millisecond)
+	if n := c.Len(); n != 0 {
+		t.Fatalf("Len after sweep = %d, want 0", n)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Memory LRU Eviction
+//||------------------------------------------------------------------------------------------------||
+
+func TestMemory_LRUEviction(t *testing.T) {
+	ctx := context.Background()
+	c := newTestMemory(t, config.CacheInstanceConfig{Shards: 1, MaxEntries: 2, Eviction: "lru"})
+
+	_ = c.Set(ctx, "a", []byte("1"), 0)
+	_ = c.Set(ctx, "b", []byte("2"), 0)
+	_, _ = c.Get(ctx, "a") // a is now most recent
+	_ = c.Set(ctx, "c", []byte("3"), 0)
+
+	if ok, _ := c.Exists(ctx, "b"); ok {
+		t.Fatal("LRU should have evicted b")
+	}
+	for _, k := range []string{"a", "c"} {
+		if ok, _ := c.Exists(ctx, k); !ok {
+			t.Fatalf("LRU evicted %s", k)
+		}
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Memory LFU Eviction
+//||------------------------------------------------------------------------------------------------||
+
+func TestMemory_LFUEviction(t *testing.T) {
+	ctx := context.Background()
+	c := newTestMemory(t, config.CacheInstanceConfig{Shards: 1, MaxEntries: 2, Eviction: "lfu"})
+
+	_ = c.Set(ctx, "hot", []byte("1"), 0)
+	_ = c.Set(ctx, "cold", []byte("2"), 0)
+	for i := 0; i < 3; i++ {
+		_, _ = c.Get(ctx, "hot")
+	}
+	_, _ = c.Get(ctx, "cold")
+	_ = c.Set(ctx, "new", []byte("3"), 0)
+
+	if ok, _ := c.Exists(ctx, "cold"); ok {
+		t.Fatal("LFU should have evicted cold")
+	}
+	if ok, _ := c.Exists(ctx, "hot"); !ok {
+		t.Fatal("LFU evicted hot")
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Memory LFU Order (fewest hits first, least recent on ties, Set keeps the hit count)
+//||------------------------------------------------------------------------------------------------||
+
+func TestMemory_LFUOrder(t *testing.T) {
+	ctx := context.Background()
+	c := newTestMemory(t, config.CacheInstanceConfig{Shards: 1, MaxEntries: 3, Eviction: "lfu"})
+	get := func(keys ...string) {
+		for _, k := range keys {
+			if _, err := c.Get(ctx, k); err != nil {
+				t.Fatalf("Get %s: %v", k, err)
+			}
+		}
+	}
+	set := func(k string) { _ = c.Set(ctx, k, []byte(k), 0) }
+	expect := func(live, gone string) {
+		t.Helper()
+		for _, k := range live {
+			if ok, _ := c.Exists(ctx, string(k)); !ok {
+				t.Fatalf("%c was evicted", k)
+			}
+		}
+		for _, k := range gone {
+			if ok, _ := c.Exists(ctx, string(k)); ok {
+				t.Fatalf("%c should have been evicted", k)
+			}
+		}
+	}
+
+	set("a")
+	set("b")
+	set("c")
+	get("b", "b", "c") // a:0 b:2 c:1
+	set("d")           // a is the only entry with no hits
+	set("e")           // d, never the key just written
+	expect("bce", "ad")
+
+	get("c") // b:2 c:2, c more recent
+	set("f")
+	get("f", "f") // b, c, f all at 2
+	set("g")      // least recent of the tie
+	expect("cfg", "abde")
+
+	set("c") // replacing keeps c's hits and refreshes it
+	set("h")
+	get("h", "h", "h")
+	set("i")
+	expect("chi", "fg")
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Memory Zero Value: calls fail instead of panicking
+//||------------------------------------------------------------------------------------------------||
+
+func TestMemory_ZeroValue(t *testing.T) {
+	ctx := context.Background()
+	var c MemoryCacheWrapper
+	if err := c.Set(ctx, "k", []byte("v"), 0); !errors.Is(err, errMemoryUninitialized) {
+		t.Fatalf("Set = %v", err)
+	}
+	if _, err := c.Get(ctx, "k"); !errors.Is(err, errMemoryUninitialized) {
+		t.Fatalf("Get = %v", err)
+	}
+	if err := c.Ping(); !errors.Is(err, errMemoryUninitialized) {
+		t.Fatalf("Ping = %v", err)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Memory Max Bytes
+//||------------------------------------------------------------------------------------------------||
+
+func TestMemory_MaxBytes(t *testing.T) {
+	ctx := context.Background()
+	c := newTestMemory(t, config.CacheInstanceConfig{Shards: 1, MaxBytes: 64})
+
+	for i := 0; i < 10; i++ {
+		_ = c.Set(ctx, fmt.Sprintf("k%d", i), make([]byte, 20), 0)
+	}
+	if n := c.Len(); n > 2 {
+		t.Fatalf("Len = %d, want <= 2 under 64 byte limit", n)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Memory Concurrent Access (run with -race)
+//||------------------------------------------------------------------------------------------------||
+
+func TestMemory_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	c := newTestMemory(t, config.CacheInstanceConfig{MaxEntries: 100})
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				key := fmt.Sprintf("k%d", (g*500+i)%150)
+				_ = c.Set(ctx, key, []byte("v"), time.Second)
+				_, _ = c.Get(ctx, key)
+				_ = c.Delete(ctx, key)
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Memory Invalid Policy
+//||------------------------------------------------------------------------------------------------||
+
+func TestMemory_InvalidEviction(t *testing.T) {
+	if _, err := NewMemoryCache("x", config.CacheInstanceConfig{Eviction: "random"}); err == nil {
+		t.Fatal("expected error for unsupported eviction policy")
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Remember De-duplicates Concurrent Loaders
+//||------------------------------------------------------------------------------------------------||
+
+func TestRemember_Dedup(t *testing.T) {
+	ctx := context.Background()
+	c := newTestMemory(t, config.CacheInstanceConfig{})
+
+	var mu sync.Mutex
+	calls := 0
+	loader := func(ctx context.Context) ([]byte, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		time.Sleep(50 * time.Millisecond)
+		return []byte("loaded"), nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := Remember(ctx, c, "r", time.Minute, loader)
+			if err != nil || string(v) != "loaded" {
+				t.Errorf("Remember = %q, %v", v, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls != 1 {
+		t.Fatalf("loader calls = %d, want 1", calls)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Remember Stale-While-Revalidate
+//||------------------------------------------------------------------------------------------------||
+
+func TestRemember_Stale(t *testing.T) {
+	ctx := context.Background()
+	c := newTestMemory(t, config.CacheInstanceConfig{})
+
+	version := 0
+	loader := func(ctx context.Context) ([]byte, error) {
+		version++
+		return []byte(fmt.Sprintf("v%d", version)), nil
+	}
+
+	v, _ := Remember(ctx, c, "s", 30*time.Millisecond, loader, WithStale(time.Second))
+	if string(v) != "v1" {
+		t.Fatalf("first = %q", v)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	v, _ = Remember(ctx, c, "s", 30*time.Millisecond, loader, WithStale(time.Second))
+	if string(v) != "v1" {
+		t.Fatalf("stale read = %q, want v1", v)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	v, _ = Remember(ctx, c, "s", 30*time.Millisecond, loader, WithStale(time.Second))
+	if string(v) != "v2" {
+		t.Fatalf("after revalidate = %q, want v2", v)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Remember: the shared load outlives a cancelled caller; waiters can give up on their own
+//||------------------------------------------------------------------------------------------------||
+
+func TestRemember_Cancellation(t *testing.T) {
+	c := newTestMemory(t, config.CacheInstanceConfig{})
+	release := make(chan struct{})
+	started := make(chan struct{})
+	loader := func(ctx context.Context) ([]byte, error) {
+		close(started)
+		<-release
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return []byte("v"), nil
+	}
+
+	first, cancelFirst := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := Remember(first, c, "k", time.Minute, loader)
+		firstErr <- err
+	}()
+	<-started
+
+	type result struct {
+		val []byte
+		err error
+	}
+	waiter := make(chan result, 1)
+	go func() {
+		v, err := Remember(context.Background(), c, "k", time.Minute, loader)
+		waiter <- result{v, err}
+	}()
+
+	short, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := Remember(short, c, "k", time.Minute, loader); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("impatient waiter err = %v, want DeadlineExceeded", err)
+	}
+	cancelFirst()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled caller err = %v, want Canceled", err)
+	}
+
+	close(release)
+	if r := <-waiter; r.err != nil || string(r.val) != "v" {
+		t.Fatalf("waiter = %q, %v; want v after the first caller was cancelled", r.val, r.err)
+	}
+	if got, err := c.Get(context.Background(), "k"); err != nil || string(got) != "v" {
+		t.Fatalf("cached = %q, %v; want v", got, err)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Remember: a panicking loader reaches every caller as ErrLoaderPanic
+//||------------------------------------------------------------------------------------------------||
+
+func TestRemember_LoaderPanic(t *testing.T) {
+	c := newTestMemory(t, config.CacheInstanceConfig{})
+	ctx := context.Background()
+	release := make(chan struct{})
+	loader := func(context.Context) ([]byte, error) {
+		<-release
+		panic("boom")
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			v, err := Remember(ctx, c, "k", time.Minute, loader)
+			if v != nil {
+				err = fmt.Errorf("value %q", v)
+			}
+			errs[i] = err
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	for i, err := range errs {
+		if !errors.Is(err, ErrLoaderPanic) {
+			t.Fatalf("caller %d err = %v, want ErrLoaderPanic", i, err)
+		}
+	}
+
+	if v, err := Remember(ctx, c, "k", time.Minute, func(context.Context) ([]byte, error) { return []byte("ok"), nil }); err != nil || string(v) != "ok" {
+		t.Fatalf("Remember after a panic = %q, %v; want the flight cleared", v, err)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test RememberJSON
+//||------------------------------------------------------------------------------------------------||
+
+func TestRememberJSON(t *testing.T) {
+	type account struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+	ctx := context.Background()
+	c := newTestMemory(t, config.CacheInstanceConfig{})
+
+	load := func(ctx context.Context) (account, error) {
+		return account{ID: 7, Name: "aria"}, nil
+	}
+	if _, err := RememberJSON(ctx, c, "acct", time.Minute, load); err != nil {
+		t.Fatalf("RememberJSON: %v", err)
+	}
+	got, err := RememberJSON(ctx, c, "acct", time.Minute, func(ctx context.Context) (account, error) {
+		return account{}, errors.New("loader should not run on hit")
+	})
+	if err != nil || got.ID != 7 || got.Name != "aria" {
+		t.Fatalf("RememberJSON hit = %+v, %v", got, err)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Redis Options by Mode
+//||------------------------------------------------------------------------------------------------||
+
+func TestBuildRedisOptions_Modes(t *testing.T) {
+	opts, err := buildRedisOptions(config.CacheInstanceConfig{Host: "localhost", Port: 6379, PoolSize: 20})
+	if err != nil || len(opts.Addrs) != 1 || opts.Addrs[0] != "localhost:6379" || opts.PoolSize != 20 {
+		t.Fatalf("single = %+v, %v", opts, err)
+	}
+
+	opts, err = buildRedisOptions(config.CacheInstanceConfig{
+		Mode:          "sentinel",
+		MasterName:    "mymaster",
+		SentinelAddrs: []string{"s1:26379", "s2:26379"},
+	})
+	if err != nil || opts.MasterName != "mymaster" || len(opts.Addrs) != 2 {
+		t.Fatalf("sentinel = %+v, %v", opts, err)
+	}
+
+	if _, err := buildRedisOptions(config.CacheInstanceConfig{Mode: "sentinel"}); err == nil {
+		t.Fatal("sentinel without master_name should fail")
+	}
+	if _, err := buildRedisOptions(config.CacheInstanceConfig{Mode: "cluster"}); err == nil {
+		t.Fatal("cluster without servers should fail")
+	}
+	if _, err := buildRedisOptions(config.CacheInstanceConfig{Mode: "ring"}); err == nil {
+		t.Fatal("unknown mode should fail")
+	}
+	opts, err = buildRedisOptions(config.CacheInstanceConfig{Host: "h", Port: 1, TLS: true, TLSServerName: "redis.internal"})
+	if err != nil || opts.TLSConfig == nil || opts.TLSConfig.ServerName != "redis.internal" {
+		t.Fatalf("tls = %+v, %v", opts, err)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Memory Lock / Unlock / Extend
+//||------------------------------------------------------------------------------------------------||
+
+func TestMemory_Lock(t *testing.T) {
+	ctx := context.Background()
+	c := newTestMemory(t, config.CacheInstanceConfig{})
+
+	lock, err := c.Lock(ctx, "job", time.Minute)
+	if err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+	if _, err := c.Lock(ctx, "job", time.Minute); !errors.Is(err, ErrLockNotAcquired) {
+		t.Fatalf("second Lock err = %v, want ErrLockNotAcquired", err)
+	}
+
+	forged := &Lock{Key: "job", Token: "not-the-token", backend: c}
+	if err := forged.Unlock(ctx); !errors.Is(err, ErrLockNotHeld) {
+		t.Fatalf("forged Unlock err = %v, want ErrLockNotHeld", err)
+	}
+	if err := lock.Extend(ctx, time.Minute); err != nil {
+		t.Fatalf("Extend: %v", err)
+	}
+	if err := lock.Unlock(ctx); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	if err := lock.Unlock(ctx); !errors.Is(err, ErrLockNotHeld) {
+		t.Fatalf("double Unlock err = %v, want ErrLockNotHeld", err)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Memory Lock Survives Eviction Pressure (locks are not cache entries)
+//||------------------------------------------------------------------------------------------------||
+
+func TestMemory_LockNotEvicted(t *testing.T) {
+	ctx := context.Background()
+	c := newTestMemory(t, config.CacheInstanceConfig{MaxEntries: 8, Eviction: "lfu"})
+
+	lock, err := c.Lock(ctx, "job", time.Minute)
+	if err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+	for i := 0; i < 1000; i++ {
+		_ = c.Set(ctx, fmt.Sprintf("k%d", i), []byte("v"), time.Minute)
+	}
+	if _, err := c.Lock(ctx, "job", time.Minute); !errors.Is(err, ErrLockNotAcquired) {
+		t.Fatalf("Lock after eviction pressure err = %v, want ErrLockNotAcquired", err)
+	}
+	if err := lock.Unlock(ctx); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+
+	if _, err := c.Lock(ctx, "short", 20*time.Millisecond); err != nil {
+		t.Fatalf("Lock short: %v", err)
+	}
+	time.Sleep(40 * time.Millisecond)
+	if _, err := c.Lock(ctx, "short", time.Minute); err != nil {
+		t.Fatalf("Lock after TTL expiry: %v", err)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| helper: a Redis cache backed by an in-process miniredis
+//||------------------------------------------------------------------------------------------------||
+
+func newTestRedis(t *testing.T) (*RedisCacheWrapper, *miniredis.Miniredis) {
+	t.Helper()
+	srv := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return &RedisCacheWrapper{Name: "test", Client: client, Ctx: context.Background()}, srv
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Redis Lock / Unlock / Extend / Expiry
+//||------------------------------------------------------------------------------------------------||
+
+func TestRedis_Lock(t *testing.T) {
+	ctx := context.Background()
+	c, srv := newTestRedis(t)
+
+	lock, err := c.Lock(ctx, "job", time.Minute)
+	if err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+	if _, err := c.Lock(ctx, "job", time.Minute); !errors.Is(err, ErrLockNotAcquired) {
+		t.Fatalf("second Lock err = %v, want ErrLockNotAcquired", err)
+	}
+	forged := &Lock{Key: "job", Token: "not-the-token", backend: c}
+	if err := forged.Unlock(ctx); !errors.Is(err, ErrLockNotHeld) {
+		t.Fatalf("forged Unlock err = %v, want ErrLockNotHeld", err)
+	}
+	if err := forged.Extend(ctx, time.Minute); !errors.Is(err, ErrLockNotHeld) {
+		t.Fatalf("forged Extend err = %v, want ErrLockNotHeld", err)
+	}
+	if err := lock.Extend(ctx, 2*time.Minute); err != nil {
+		t.Fatalf("Extend: %v", err)
+	}
+	if ttl := srv.TTL(lockKey("job")); ttl != 2*time.Minute {
+		t.Fatalf("TTL after Extend = %s, want 2m", ttl)
+	}
+	if err := lock.Unlock(ctx); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	if err := lock.Unlock(ctx); !errors.Is(err, ErrLockNotHeld) {
+		t.Fatalf("double Unlock err = %v, want ErrLockNotHeld", err)
+	}
+
+	if _, err := c.Lock(ctx, "short", time.Second); err != nil {
+		t.Fatalf("Lock short: %v", err)
+	}
+	srv.FastForward(2 * time.Second)
+	if _, err := c.Lock(ctx, "short", time.Minute); err != nil {
+		t.Fatalf("Lock after TTL expiry: %v", err)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Lock TTLs under 1ms are rejected by both backends (0 would never expire on Redis)
+//||------------------------------------------------------------------------------------------------||
+
+func TestLock_RejectsShortTTL(t *testing.T) {
+	ctx := context.Background()
+	r, srv := newTestRedis(t)
+	for name, l := range map[string]Locker{"redis": r, "memory": newTestMemory(t, config.CacheInstanceConfig{})} {
+		for _, ttl := range []time.Duration{0, -time.Second, 999 * time.Microsecond} {
+			if _, err := l.Lock(ctx, "k", ttl); !errors.Is(err, ErrLockTTL) {
+				t.Errorf("%s Lock(ttl %s) err = %v, want ErrLockTTL", name, ttl, err)
+			}
+			if _, err := AcquireLock(ctx, l, "k", ttl); !errors.Is(err, ErrLockTTL) {
+				t.Errorf("%s AcquireLock(ttl %s) err = %v, want ErrLockTTL", name, ttl, err)
+			}
+		}
+		lock, err := l.Lock(ctx, "held", time.Minute)
+		if err != nil {
+			t.Fatalf("%s Lock: %v", name, err)
+		}
+		if err := lock.Extend(ctx, 500*time.Microsecond); !errors.Is(err, ErrLockTTL) {
+			t.Errorf("%s Extend(500µs) err = %v, want ErrLockTTL", name, err)
+		}
+		if _, err := l.Lock(ctx, "held", time.Minute); !errors.Is(err, ErrLockNotAcquired) {
+			t.Errorf("%s lock released by a rejected Extend: %v", name, err)
+		}
+	}
+	if srv.Exists(lockKey("k")) {
+		t.Fatal("a rejected Lock left a key in Redis")
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test AcquireLock Blocks Until Released / Times Out
+//||------------------------------------------------------------------------------------------------||
+
+func TestAcquireLock(t *testing.T) {
+	ctx := context.Background()
+	c := newTestMemory(t, config.CacheInstanceConfig{})
+
+	held, _ := c.Lock(ctx, "acct:1", time.Minute)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = held.Unlock(ctx)
+	}()
+	lock, err := AcquireLock(ctx, c, "acct:1", time.Minute, WithLockBackoff(5*time.Millisecond, 20*time.Millisecond))
+	if err != nil {
+		t.Fatalf("AcquireLock: %v", err)
+	}
+
+	tctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := AcquireLock(tctx, c, "acct:1", time.Minute); !errors.Is(err, ErrLockNotAcquired) {
+		t.Fatalf("AcquireLock on held lock err = %v", err)
+	}
+	_ = lock.Unlock(ctx)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Memory Tag Invalidation
+//||------------------------------------------------------------------------------------------------||
+
+func TestMemory_Tags(t *testing.T) {
+	ctx := context.Background()
+	c := newTestMemory(t, config.CacheInstanceConfig{})
+
+	_ = c.SetWithTags(ctx, "acct:1:profile", []byte("p"), time.Minute, "acct:1")
+	_ = c.SetWithTags(ctx, "acct:1:prefs", []byte("q"), time.Minute, "acct:1", "prefs")
+	_ = c.SetWithTags(ctx, "acct:2:profile", []byte("r"), time.Minute, "acct:2")
+
+	if err := c.InvalidateTag(ctx, "acct:1"); err != nil {
+		t.Fatalf("InvalidateTag: %v", err)
+	}
+	for _, k := range []string{"acct:1:profile", "acct:1:prefs"} {
+		if ok, _ := c.Exists(ctx, k); ok {
+			t.Fatalf("%s survived InvalidateTag", k)
+		}
+	}
+	if ok, _ := c.Exists(ctx, "acct:2:profile"); !ok {
+		t.Fatal("acct:2:profile should not be invalidated")
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Redis Tag Invalidation (members, sub-ms TTL, concurrent SetWithTags)
+//||------------------------------------------------------------------------------------------------||
+
+func TestRedis_Tags(t *testing.T) {
+	ctx := context.Background()
+	c, srv := newTestRedis(t)
+
+	_ = c.SetWithTags(ctx, "acct:1:profile", []byte("p"), time.Minute, "acct:1")
+	_ = c.SetWithTags(ctx, "acct:1:prefs", []byte("q"), time.Minute, "acct:1", "prefs")
+	_ = c.SetWithTags(ctx, "acct:2:profile", []byte("r"), time.Minute, "acct:2")
+
+	if err := c.InvalidateTag(ctx, "acct:1"); err != nil {
+		t.Fatalf("InvalidateTag: %v", err)
+	}
+	for _, k := range []string{"acct:1:profile", "acct:1:prefs"} {
+		if srv.Exists(k) {
+			t.Fatalf("%s survived InvalidateTag", k)
+		}
+	}
+	if !srv.Exists("acct:2:profile") {
+		t.Fatal("acct:2:profile should not be invalidated")
+	}
+	if srv.Exists(tagKey("acct:1")) {
+		t.Fatal("emptied tag set was not removed")
+	}
+
+	// a sub-millisecond TTL still expires the tag set
+	_ = c.SetWithTags(ctx, "short", []byte("s"), 500*time.Microsecond, "short")
+	if ttl := srv.TTL(tagKey("short")); ttl <= 0 {
+		t.Fatalf("tag set ttl = %v, want it to expire", ttl)
+	}
+
+	// every key that survives concurrent invalidations is still tagged
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			_ = c.SetWithTags(ctx, fmt.Sprintf("race:%d", i), []byte("v"), time.Minute, "race")
+		}
+	}()
+	for i := 0; i < 50; i++ {
+		if err := c.InvalidateTag(ctx, "race"); err != nil {
+			t.Fatalf("InvalidateTag: %v", err)
+		}
+	}
+	wg.Wait()
+	for i := 0; i < 200; i++ {
+		key := fmt.Sprintf("race:%d", i)
+		if !srv.Exists(key) {
+			continue
+		}
+		if ok, _ := srv.SIsMember(tagKey("race"), key); !ok {
+			t.Fatalf("%s is live but no longer tagged", key)
+		}
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Registry (custom backend with a dependency, unknown backend, cycle)
+//||------------------------------------------------------------------------------------------------||
+
+func TestRegistry_CustomBackend(t *testing.T) {
+	var gotDep Cache
+	Register("test-wrap", func(name string, cfg config.CacheInstanceConfig, deps Resolver) (Cache, error) {
+		dep, err := deps(cfg.L2)
+		if err != nil {
+			return nil, err
+		}
+		gotDep = dep
+		return NewMemoryCache(name, cfg)
+	})
+
+	cfg := &config.Config{Cache: map[string]config.CacheInstanceConfig{
+		"a": {Backend: "TEST-WRAP", L2: "z"},
+		"z": {Backend: "memory"},
+	}}
+	caches, err := Init(cfg)
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	t.Cleanup(func() { _ = closeAll(caches) })
+	if len(caches) != 2 || gotDep != caches["z"] {
+		t.Fatalf("dependency not resolved to built cache: %v", caches)
+	}
+
+	if _, err := Init(&config.Config{Cache: map[string]config.CacheInstanceConfig{"x": {Backend: "nope"}}}); err == nil {
+		t.Fatal("expected error for unregistered backend")
+	}
+
+	cycle := &config.Config{Cache: map[string]config.CacheInstanceConfig{
+		"a": {Backend: "test-wrap", L2: "b"},
+		"b": {Backend: "test-wrap", L2: "a"},
+	}}
+	if _, err := Init(cycle); err == nil {
+		t.Fatal("expected error for dependency cycle")
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Layered helpers: a fake pub/sub hub and nodes sharing one memory L2
+//||------------------------------------------------------------------------------------------------||
+
+type fakeHub struct {
+	mu   sync.Mutex
+	subs []*fakeBus
+}
+
+type fakeBus struct {
+	hub    *fakeHub
+	ch     chan *redis.Message
+	closed bool
+}
+
+func (h *fakeHub) bus() *fakeBus {
+	b := &fakeBus{hub: h, ch: make(chan *redis.Message, 64)}
+	h.mu.Lock()
+	h.subs = append(h.subs, b)
+	h.mu.Unlock()
+	return b
+}
+
+func (b *fakeBus) Publish(ctx context.Context, payload []byte) error {
+	b.hub.mu.Lock()
+	defer b.hub.mu.Unlock()
+	for _, sub := range b.hub.subs {
+		if !sub.closed {
+			sub.ch <- &redis.Message{Payload: string(payload)}
+		}
+	}
+	return nil
+}
+
+func (b *fakeBus) Channel() <-chan *redis.Message { return b.ch }
+
+func (b *fakeBus) Close() error {
+	b.hub.mu.Lock()
+	defer b.hub.mu.Unlock()
+	close(b.ch)
+	b.closed = true
+	return nil
+}
+
+func newTestLayered(t *testing.T, hub *fakeHub, l2 LayeredL2) *LayeredCacheWrapper {
+	t.Helper()
+	l1, err := NewMemoryCache("l1", config.CacheInstanceConfig{Backend: "memory"})
+	if err != nil {
+		t.Fatalf("NewMemoryCache: %v", err)
+	}
+	c := newLayeredWithBus("layered", l1, l2, time.Minute, "test", hub.bus())
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+// eventually polls cond until it holds or a second passes.
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Layered: L2 hits are backfilled into L1
+//||------------------------------------------------------------------------------------------------||
+
+func TestLayered_ReadThrough(t *testing.T) {
+	ctx := context.Background()
+	l2 := newTestMemory(t, config.CacheInstanceConfig{})
+	c := newTestLayered(t, &fakeHub{}, l2)
+
+	_ = l2.Set(ctx, "k", []byte("v"), time.Minute)
+	if val, err := c.Get(ctx, "k"); err != nil || string(val) != "v" {
+		t.Fatalf("Get = %q, %v", val, err)
+	}
+	if val, err := c.L1.Get(ctx, "k"); err != nil || string(val) != "v" {
+		t.Fatalf("L1 after read-through = %q, %v; want backfilled v", val, err)
+	}
+	if _, err := c.Get(ctx, "missing"); err == nil {
+		t.Fatal("Get of a key missing from both tiers succeeded")
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Layered: writes, deletes and tag invalidations on one node drop the others' L1 copies
+//||------------------------------------------------------------------------------------------------||
+
+func TestLayered_InvalidationFanOut(t *testing.T) {
+	ctx := context.Background()
+	hub := &fakeHub{}
+	l2 := newTestMemory(t, config.CacheInstanceConfig{})
+	a, b := newTestLayered(t, hub, l2), newTestLayered(t, hub, l2)
+
+	_ = a.Set(ctx, "k", []byte("v1"), time.Minute)
+	if val, _ := b.Get(ctx, "k"); string(val) != "v1" {
+		t.Fatalf("b.Get = %q, want v1", val)
+	}
+	_ = a.Set(ctx, "k", []byte("v2"), time.Minute)
+	eventually(t, "b to see v2", func() bool { val, _ := b.Get(ctx, "k"); return string(val) == "v2" })
+
+	_ = a.Delete(ctx, "k")
+	eventually(t, "b to drop k", func() bool { _, err := b.Get(ctx, "k"); return err != nil })
+
+	_ = a.SetWithTags(ctx, "t", []byte("x"), time.Minute, "grp")
+	if _, err := b.Get(ctx, "t"); err != nil {
+		t.Fatalf("b.Get(t): %v", err)
+	}
+	_ = b.L1.SetWithTags(ctx, "t", []byte("x"), time.Minute, "grp") // b's copy carries the tag
+	_ = a.InvalidateTag(ctx, "grp")
+	eventually(t, "b to drop tagged t", func() bool { ok, _ := b.L1.Exists(ctx, "t"); return !ok })
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Layered: an invalidation that lands during the L2 read stops the stale backfill
+//||------------------------------------------------------------------------------------------------||
+
+type blockingL2 struct {
+	LayeredL2
+	once    sync.Once
+	reading chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingL2) Get(ctx context.Context, key string) ([]byte, error) {
+	val, err := b.LayeredL2.Get(ctx, key)
+	b.once.Do(func() {
+		close(b.reading)
+		<-b.release
+	})
+	return val, err
+}
+
+func TestLayered_BackfillRace(t *testing.T) {
+	ctx := context.Background()
+	hub := &fakeHub{}
+	l2 := newTestMemory(t, config.CacheInstanceConfig{})
+	slow := &blockingL2{LayeredL2: l2, reading: make(chan struct{}), release: make(chan struct{})}
+	writer, reader := newTestLayered(t, hub, l2), newTestLayered(t, hub, slow)
+
+	_ = l2.Set(ctx, "k", []byte("old"), time.Minute)
+	got := make(chan []byte)
+	go func() {
+		val, _ := reader.Get(ctx, "k")
+		got <- val
+	}()
+	<-slow.reading // reader holds "old" from L2 but has not backfilled yet
+
+	gen := func() uint64 {
+		s := reader.stripe("k")
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.gen
+	}
+	before := gen()
+	_ = writer.Set(ctx, "k", []byte("new"), time.Minute)
+	eventually(t, "reader to receive the invalidation", func() bool { return gen() != before })
+
+	close(slow.release)
+	if val := <-got; string(val) != "old" {
+		t.Fatalf("in-flight Get = %q, want old", val)
+	}
+	if val, err := reader.L1.Get(ctx, "k"); err == nil {
+		t.Fatalf("stale %q backfilled into L1 after invalidation", val)
+	}
+	if val, _ := reader.Get(ctx, "k"); string(val) != "new" {
+		t.Fatalf("reader.Get = %q, want new", val)
+	}
+}
@@ -0,0 +1,218 @@
+//||------------------------------------------------------------------------------------------------||
+//|| Config Package: Adapters (typed integration settings under "adapters")
+//|| adapters.go
+//||------------------------------------------------------------------------------------------------||
+
+package config
+
+//||------------------------------------------------------------------------------------------------||
+//|| Import
+//||------------------------------------------------------------------------------------------------||
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| AdapterValidator: implemented by adapter structs that check their own settings
+//||
+//|| Returning a *ValidationError reports each problem under "adapters.<name>.<path>".
+//||------------------------------------------------------------------------------------------------||
+
+type AdapterValidator interface {
+	Validate() error
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Registry: adapter name -> struct type
+//||------------------------------------------------------------------------------------------------||
+
+var (
+	adaptersMu sync.RWMutex
+	adapters   = map[string]reflect.Type{}
+
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	anyType        = reflect.TypeOf((*any)(nil)).Elem()
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| RegisterAdapter: config.RegisterAdapter[StripeConfig]("stripe")
+//||
+//|| Registered adapters are strictly decoded (unknown fields rejected) and validated on load, and
+//|| appear in JSONSchema. Unregistered entries are kept as raw JSON and only env/secret expanded.
+//||------------------------------------------------------------------------------------------------||
+
+func RegisterAdapter[T any](name string) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if name == "" || t.Kind() != reflect.Struct {
+		panic("config: RegisterAdapter requires a name and a struct type")
+	}
+	adaptersMu.Lock()
+	adapters[strings.ToLower(name)] = t
+	adaptersMu.Unlock()
+}
+
+// Adapters returns the registered adapter names (sorted).
+func Adapters() []string {
+	adaptersMu.RLock()
+	defer adaptersMu.RUnlock()
+	out := make([]string, 0, len(adapters))
+	for name := range adapters {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func adapterType(name string) (reflect.Type, bool) {
+	adaptersMu.RLock()
+	defer adaptersMu.RUnlock()
+	t, ok := adapters[strings.ToLower(name)]
+	return t, ok
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Adapter: decode the "adapters.<name>" block of c into T (a fresh copy on every call)
+//||------------------------------------------------------------------------------------------------||
+
+func Adapter[T any](c *Config, name string) (T, error) {
+	var out T
+	if c == nil {
+		return out, fmt.Errorf("adapter %q: config not loaded", name)
+	}
+	if t, ok := adapterType(name); ok && t != reflect.TypeOf(out) {
+		return out, fmt.Errorf("adapter %q is registered as %s, not %T", name, t, out)
+	}
+	raw, ok := c.Adapters[matchAdapterKey(c.Adapters, name)]
+	if !ok {
+		return out, fmt.Errorf("adapter %q not configured", name)
+	}
+	if err := decodeStrict(raw, &out); err != nil {
+		return out, fmt.Errorf("adapter %q: %w", name, err)
+	}
+	return out, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| MustAdapter: Adapter against the loaded singleton (panics when missing or invalid)
+//||------------------------------------------------------------------------------------------------||
+
+func MustAdapter[T any](name string) T {
+	out, err := Adapter[T](Must(), name)
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| expandAdapters: os.ExpandEnv then secret references on every string (runs with resolveSecrets)
+//||------------------------------------------------------------------------------------------------||
+
+func expandAdapters(c *Config) error {
+	var errs []string
+	for name, raw := range c.Adapters {
+		var tree any
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		if err := dec.Decode(&tree); err != nil {
+			errs = append(errs, fmt.Sprintf("adapters.%s: %v", name, err))
+			continue
+		}
+		tree = expandTree(tree, "adapters."+name, &errs)
+		out, err := json.Marshal(tree)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("adapters.%s: %v", name, err))
+			continue
+		}
+		c.Adapters[name] = out
+	}
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return fmt.Errorf("adapters: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func expandTree(node any, path string, errs *[]string) any {
+	switch n := node.(type) {
+	case string:
+		s, err := ResolveSecret(os.ExpandEnv(n))
+		if err != nil {
+			*errs = append(*errs, fmt.Sprintf("%s: %v", path, err))
+			return n
+		}
+		return s
+	case map[string]any:
+		for k, v := range n {
+			n[k] = expandTree(v, path+"."+k, errs)
+		}
+	case []any:
+		for i, v := range n {
+			n[i] = expandTree(v, fmt.Sprintf("%s.%d", path, i), errs)
+		}
+	}
+	return node
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| adapter (validator): strict decode into the registered type, then its own Validate
+//||------------------------------------------------------------------------------------------------||
+
+func (v *validator) adapter(name string, raw json.RawMessage) {
+	t, ok := adapterType(name)
+	if !ok {
+		return
+	}
+	path := "adapters." + name
+	ptr := reflect.New(t)
+	if err := decodeStrict(raw, ptr.Interface()); err != nil {
+		v.add(path, "%v", err)
+		return
+	}
+	av, ok := ptr.Interface().(AdapterValidator)
+	if !ok {
+		return
+	}
+	err := av.Validate()
+	var verr *ValidationError
+	switch {
+	case err == nil:
+	case errors.As(err, &verr):
+		for _, p := range verr.Problems {
+			v.add(path+"."+p.Path, "%s", p.Message)
+		}
+	default:
+		v.add(path, "%v", err)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Helpers
+//||------------------------------------------------------------------------------------------------||
+
+func decodeStrict(raw json.RawMessage, out any) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	return dec.Decode(out)
+}
+
+func matchAdapterKey(m map[string]json.RawMessage, name string) string {
+	if _, ok := m[name]; ok {
+		return name
+	}
+	for k := range m {
+		if strings.EqualFold(k, name) {
+			return k
+		}
+	}
+	return name
+}
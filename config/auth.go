@@ -5,7 +5,10 @@ package config
 //||------------------------------------------------------------------------------------------------||
 
 type AuthConfig struct {
-	Pepper string
-	CSRF   string
-	Table  string
+	Pepper        string `json:"pepper,omitempty"`
+	CSRF          string `json:"csrf,omitempty"`
+	Table         string `json:"table,omitempty"`
+	JWTSecret     string `json:"jwt_secret,omitempty"`
+	SessionExpiry int    `json:"session_expiry,omitempty"` // seconds
+	TokenIssuer   string `json:"token_issuer,omitempty"`
 }
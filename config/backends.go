@@ -0,0 +1,91 @@
+//||------------------------------------------------------------------------------------------------||
+//|| Config Package: Backend Registry
+//|| backends.go
+//||------------------------------------------------------------------------------------------------||
+
+package config
+
+//||------------------------------------------------------------------------------------------------||
+//|| Import
+//||------------------------------------------------------------------------------------------------||
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Backend Sections
+//||------------------------------------------------------------------------------------------------||
+
+const (
+	SectionDB      = "db"
+	SectionCache   = "cache"
+	SectionStorage = "storage"
+	SectionQueue   = "queue"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Registry: section -> backend names (filled by each package's Register)
+//||------------------------------------------------------------------------------------------------||
+
+var (
+	backendsMu sync.RWMutex
+	backends   = map[string]map[string]struct{}{}
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Seed: database drivers are always validated, even when db is not linked
+//||------------------------------------------------------------------------------------------------||
+
+func init() {
+	RegisterBackend(SectionDB, "postgres", "mysql", "mariadb", "mongo")
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| RegisterBackend: record backend names that validate() will accept
+//||------------------------------------------------------------------------------------------------||
+
+func RegisterBackend(section string, names ...string) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+	set, ok := backends[section]
+	if !ok {
+		set = map[string]struct{}{}
+		backends[section] = set
+	}
+	for _, name := range names {
+		set[strings.ToLower(name)] = struct{}{}
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Backends: sorted list of registered names for a section
+//||------------------------------------------------------------------------------------------------||
+
+func Backends(section string) []string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	out := make([]string, 0, len(backends[section]))
+	for name := range backends[section] {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| backendKnown: (known, checked) — unchecked when nothing registered the section
+//||------------------------------------------------------------------------------------------------||
+
+func backendKnown(section, name string) (bool, bool) {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	set, ok := backends[section]
+	if !ok || len(set) == 0 {
+		return false, false
+	}
+	_, known := set[strings.ToLower(name)]
+	return known, true
+}
@@ -5,12 +5,22 @@ package config
 //||------------------------------------------------------------------------------------------------||
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"sort"
 	"strings"
 )
 
+//||------------------------------------------------------------------------------------------------||
+//|| secretFields: always printed as ******* (values may come from files, env vars or flags)
+//||------------------------------------------------------------------------------------------------||
+
+var secretFields = []string{
+	"Password", "SentinelPassword", "AccessKey", "SecretKey",
+	"CredentialsJSON", "Salt", "Pepper", "CSRF", "JWTSecret", "SignKey",
+}
+
 //||------------------------------------------------------------------------------------------------||
 //|| PrintConsoleConfig: Print structured config by section
 //||------------------------------------------------------------------------------------------------||
@@ -120,7 +130,7 @@ func printValue(
 	//|| Mask
 	//||------------------------------------------------------------------------------------------------||
 
-	if stringInSlice(field, []string{"Password", "AccessKey", "SecretKey"}) {
+	if stringInSlice(field, secretFields) {
 		fmt.Printf("%s%s : %s\n", pad, field, "*******")
 		return
 	}
@@ -129,7 +139,7 @@ func printValue(
 	//|| Hide
 	//||------------------------------------------------------------------------------------------------||
 
-	if stringInSlice(field, []string{"Middleware", "ErrorHandler", "DB", "Servers", "Dir", "URI", "SSLMode"}) {
+	if _, raw := data.(json.RawMessage); raw || stringInSlice(field, []string{"Middleware", "ErrorHandler", "DB", "Servers", "Dir", "URI", "SSLMode"}) {
 		fmt.Printf("%s%s : %s\n", pad, field, "*******")
 		return
 	}
@@ -0,0 +1,30 @@
+//||------------------------------------------------------------------------------------------------||
+//|| Config Package: Instance Errors
+//|| errors.go
+//||------------------------------------------------------------------------------------------------||
+
+package config
+
+//||------------------------------------------------------------------------------------------------||
+//|| Import
+//||------------------------------------------------------------------------------------------------||
+
+import "fmt"
+
+//||------------------------------------------------------------------------------------------------||
+//|| InstanceError: a failure tied to one named entry of a config section (e.g. cache "auth")
+//||------------------------------------------------------------------------------------------------||
+
+type InstanceError struct {
+	Section string
+	Name    string
+	Err     error
+}
+
+func (e *InstanceError) Error() string {
+	return fmt.Sprintf("%s '%s': %v", e.Section, e.Name, e.Err)
+}
+
+func (e *InstanceError) Unwrap() error {
+	return e.Err
+}
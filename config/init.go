@@ -10,7 +10,6 @@ package config
 //||------------------------------------------------------------------------------------------------||
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -22,72 +21,54 @@ import (
 //||------------------------------------------------------------------------------------------------||
 
 var (
-	cfg  *Config
-	once sync.Once
-	mu   sync.RWMutex
+	once sync.Once // the singleton itself is defaultSource (watch.go)
 )
 
 //||------------------------------------------------------------------------------------------------||
-//|| Init
+//|| Init: load path (plus its env overlay and ARIA_ env vars) into the singleton
 //||------------------------------------------------------------------------------------------------||
 
 func Init(
 	path string,
 ) (*Config, error) {
+	return InitWithOptions(LoadOptions{Path: path})
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| InitWithOptions: Init with every layer configurable (see LoadOptions)
+//||------------------------------------------------------------------------------------------------||
+
+func InitWithOptions(
+	opts LoadOptions,
+) (*Config, error) {
 
 	var loadErr error
 
 	once.Do(func() {
 
 		//||------------------------------------------------------------------------------------------------||
-		//|| Load JSON Config
+		//|| Load Every Layer
 		//||------------------------------------------------------------------------------------------------||
 
-		file, err := os.Open(path)
+		local, err := Load(opts)
 		if err != nil {
-			loadErr = fmt.Errorf("failed to open config file: %w", err)
+			loadErr = err
 			return
 		}
-		defer file.Close()
-
-		local := &Config{}
-		dec := json.NewDecoder(file)
-		dec.DisallowUnknownFields()
-
-		if err := dec.Decode(local); err != nil {
-			loadErr = fmt.Errorf("decode config: %w", err)
-			return
-		}
-
-		//||------------------------------------------------------------------------------------------------||
-		//|| Expand Env Vars
-		//||------------------------------------------------------------------------------------------------||
-
-		expandEnvStrings(local)
 
 		//||------------------------------------------------------------------------------------------------||
-		//|| Print Debug Config Dump (optional)
+		//|| Print Debug Config Dump (app.debug only, secrets redacted)
 		//||------------------------------------------------------------------------------------------------||
 
-		//PrintConsoleConfig(local)
-
-		//||------------------------------------------------------------------------------------------------||
-		//|| Normalize + Validate
-		//||------------------------------------------------------------------------------------------------||
-
-		normalize(local)
-		if err := validate(local); err != nil {
-			loadErr = fmt.Errorf("invalid config: %w", err)
-			return
+		if local.App.Debug {
+			PrintConsoleConfig(local)
 		}
 
 		//||------------------------------------------------------------------------------------------------||
 		//|| Assign to Global Singleton
 		//||------------------------------------------------------------------------------------------------||
 
-		mu.Lock()
-		cfg = local
-		mu.Unlock()
+		defaultSource.set(opts, local)
 	})
 
 	//||------------------------------------------------------------------------------------------------||
@@ -98,14 +79,12 @@ func Init(
 		return nil, loadErr
 	}
 
-	mu.RLock()
-	defer mu.RUnlock()
-
-	if cfg == nil {
+	c := defaultSource.Config()
+	if c == nil {
 		return nil, fmt.Errorf("config not loaded")
 	}
 
-	return cfg, nil
+	return c, nil
 }
 
 //||------------------------------------------------------------------------------------------------||
@@ -113,7 +92,8 @@ func Init(
 //||------------------------------------------------------------------------------------------------||
 
 func InProduction() bool {
-	return (cfg.App.Env == "production")
+	c := GetConfig()
+	return c != nil && c.App.Env == "production"
 }
 
 //||------------------------------------------------------------------------------------------------||
@@ -121,9 +101,7 @@ func InProduction() bool {
 //||------------------------------------------------------------------------------------------------||
 
 func GetConfig() *Config {
-	mu.RLock()
-	defer mu.RUnlock()
-	return cfg
+	return defaultSource.Config()
 }
 
 //||------------------------------------------------------------------------------------------------||
@@ -143,9 +121,7 @@ func Must() *Config {
 //||------------------------------------------------------------------------------------------------||
 
 func Reset() {
-	mu.Lock()
-	defer mu.Unlock()
-	cfg = nil
+	defaultSource.set(LoadOptions{}, nil)
 	// allow re-Init after Reset
 	once = sync.Once{}
 }
@@ -174,7 +150,18 @@ func expandEnvStrings(c *Config) {
 	for k, v := range c.Cache {
 		v.Backend = os.ExpandEnv(v.Backend)
 		v.Host = os.ExpandEnv(v.Host)
+		v.Mode = os.ExpandEnv(v.Mode)
+		v.Username = os.ExpandEnv(v.Username)
 		v.Password = os.ExpandEnv(v.Password)
+		v.MasterName = os.ExpandEnv(v.MasterName)
+		v.SentinelPassword = os.ExpandEnv(v.SentinelPassword)
+		v.TLSCertFile = os.ExpandEnv(v.TLSCertFile)
+		v.TLSKeyFile = os.ExpandEnv(v.TLSKeyFile)
+		v.TLSCAFile = os.ExpandEnv(v.TLSCAFile)
+		v.TLSServerName = os.ExpandEnv(v.TLSServerName)
+		v.Eviction = os.ExpandEnv(v.Eviction)
+		v.L2 = os.ExpandEnv(v.L2)
+		v.Channel = os.ExpandEnv(v.Channel)
 		c.Cache[k] = v
 	}
 
@@ -187,6 +174,8 @@ func expandEnvStrings(c *Config) {
 		v.SecretKey = os.ExpandEnv(v.SecretKey)
 		v.Endpoint = os.ExpandEnv(v.Endpoint)
 		v.Dir = os.ExpandEnv(v.Dir)
+		v.PublicURL = os.ExpandEnv(v.PublicURL)
+		v.SignKey = os.ExpandEnv(v.SignKey)
 		c.Storage[k] = v
 	}
 
@@ -210,6 +199,8 @@ func expandEnvStrings(c *Config) {
 	c.Auth.CSRF = os.ExpandEnv(c.Auth.CSRF)
 	c.Auth.Pepper = os.ExpandEnv(c.Auth.Pepper)
 	c.Auth.Table = os.ExpandEnv(c.Auth.Table)
+	c.Auth.JWTSecret = os.ExpandEnv(c.Auth.JWTSecret)
+	c.Auth.TokenIssuer = os.ExpandEnv(c.Auth.TokenIssuer)
 
 	// Locale
 	c.Locale.Default = os.ExpandEnv(c.Locale.Default)
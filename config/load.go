@@ -0,0 +1,379 @@
+//||------------------------------------------------------------------------------------------------||
+//|| Config Package: Layered Loading (file -> env file -> ARIA_ env vars -> flags)
+//|| load.go
+//||------------------------------------------------------------------------------------------------||
+
+package config
+
+//||------------------------------------------------------------------------------------------------||
+//|| Import
+//||------------------------------------------------------------------------------------------------||
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Defaults
+//||------------------------------------------------------------------------------------------------||
+
+const (
+	DefaultEnvPrefix = "ARIA_"
+	envSeparator     = "__"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| LoadOptions
+//||
+//|| Precedence (lowest -> highest):
+//||   1. Path                        config.json | config.yaml | config.yml | config.toml
+//||   2. <stem>.<env><ext>           e.g. config.production.json, next to Path (skipped if absent)
+//||   3. <prefix><SECTION>__<...>    e.g. ARIA_DB__MAIN__HOST=10.0.0.5
+//||   4. Overrides                   e.g. -set db.main.host=10.0.0.5
+//||
+//|| env is Env, else $<prefix>APP__ENV, else app.env from the base file.
+//||------------------------------------------------------------------------------------------------||
+
+type LoadOptions struct {
+	Path      string
+	Env       string
+	EnvPrefix string   // "" = ARIA_
+	NoEnvVars bool     // skip layer 3
+	Overrides []string // "dotted.path=value"
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| BindFlags: -config, -env and repeatable -set on fs (parse fs, then pass opts to Load/InitWithOptions)
+//||------------------------------------------------------------------------------------------------||
+
+func BindFlags(fs *flag.FlagSet, opts *LoadOptions) {
+	fs.StringVar(&opts.Path, "config", opts.Path, "config file (.json, .yaml, .yml, .toml)")
+	fs.StringVar(&opts.Env, "env", opts.Env, "environment overlay, loads config.<env>.<ext>")
+	fs.Var((*overrideFlag)(&opts.Overrides), "set", "override a config value, e.g. -set db.main.host=localhost (repeatable)")
+}
+
+type overrideFlag []string
+
+func (f *overrideFlag) String() string     { return strings.Join(*f, ",") }
+func (f *overrideFlag) Set(v string) error { *f = append(*f, v); return nil }
+
+//||------------------------------------------------------------------------------------------------||
+//|| Load: build a Config from every layer (no singleton; Init/InitWithOptions wrap this)
+//||------------------------------------------------------------------------------------------------||
+
+func Load(opts LoadOptions) (*Config, error) {
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Base File
+	//||------------------------------------------------------------------------------------------------||
+
+	tree, err := readLayer(opts.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Environment File
+	//||------------------------------------------------------------------------------------------------||
+
+	prefix := opts.EnvPrefix
+	if prefix == "" {
+		prefix = DefaultEnvPrefix
+	}
+	env := opts.Env
+	if env == "" && !opts.NoEnvVars {
+		env = os.Getenv(prefix + "APP" + envSeparator + "ENV")
+	}
+	if env == "" {
+		if app, ok := tree["app"].(map[string]any); ok {
+			env, _ = app["env"].(string)
+		}
+	}
+	if env = os.ExpandEnv(env); env != "" {
+		overlay, found, err := readEnvLayer(opts.Path, env)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			mergeTree(tree, overlay)
+		}
+	}
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Environment Variables
+	//||------------------------------------------------------------------------------------------------||
+
+	if !opts.NoEnvVars {
+		for _, kv := range os.Environ() {
+			name, value, _ := strings.Cut(kv, "=")
+			if !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			path := strings.Split(strings.ToLower(strings.TrimPrefix(name, prefix)), envSeparator)
+			if !isSection(path[0]) {
+				continue // unrelated ARIA_* variable
+			}
+			if err := setPath(tree, path, value); err != nil {
+				return nil, fmt.Errorf("env %s: %w", name, err)
+			}
+		}
+	}
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Overrides (CLI flags)
+	//||------------------------------------------------------------------------------------------------||
+
+	for _, o := range opts.Overrides {
+		key, value, ok := strings.Cut(o, "=")
+		if !ok {
+			return nil, fmt.Errorf("override %q: want path=value", o)
+		}
+		if err := setPath(tree, strings.Split(strings.TrimSpace(key), "."), value); err != nil {
+			return nil, fmt.Errorf("override %q: %w", key, err)
+		}
+	}
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Decode (json tags stay the single schema for every format)
+	//||------------------------------------------------------------------------------------------------||
+
+	stripSchemaKey(tree)
+	raw, err := json.Marshal(tree)
+	if err != nil {
+		return nil, fmt.Errorf("encode merged config: %w", err)
+	}
+	local := &Config{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(local); err != nil {
+		return nil, fmt.Errorf("decode config: %w", err)
+	}
+
+	//||------------------------------------------------------------------------------------------------||
+	//|| Expand + Resolve Secrets + Normalize + Validate
+	//||------------------------------------------------------------------------------------------------||
+
+	expandEnvStrings(local)
+	if err := resolveSecrets(local); err != nil {
+		return nil, err
+	}
+	if err := expandAdapters(local); err != nil {
+		return nil, err
+	}
+	normalize(local)
+	if err := Validate(local); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return local, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| readLayer: decode one file into a generic tree, format chosen by extension
+//||------------------------------------------------------------------------------------------------||
+
+func readLayer(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open config file: %w", err)
+	}
+	tree := map[string]any{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json", "":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		err = dec.Decode(&tree)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &tree)
+	case ".toml":
+		err = toml.Unmarshal(data, &tree)
+	default:
+		return nil, fmt.Errorf("config file %s: unsupported format %q (json, yaml, yml, toml)", path, ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", filepath.Base(path), err)
+	}
+	return tree, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| readEnvLayer: <stem>.<env>.json next to base, then the base's own extension
+//||------------------------------------------------------------------------------------------------||
+
+func readEnvLayer(base, env string) (map[string]any, bool, error) {
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	exts := []string{".json"}
+	if !strings.EqualFold(ext, ".json") {
+		exts = append(exts, ext)
+	}
+	for _, e := range exts {
+		path := stem + "." + env + e
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		tree, err := readLayer(path)
+		return tree, err == nil, err
+	}
+	return nil, false, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| mergeTree: src over dst; objects merge key by key, everything else replaces
+//||------------------------------------------------------------------------------------------------||
+
+func mergeTree(dst, src map[string]any) {
+	for k, v := range src {
+		k = matchKey(dst, k)
+		if sv, ok := v.(map[string]any); ok {
+			if dv, ok := dst[k].(map[string]any); ok {
+				mergeTree(dv, sv)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| setPath: parse value against the Config field at path and store it in tree
+//||------------------------------------------------------------------------------------------------||
+
+func setPath(tree map[string]any, path []string, value string) error {
+	t := reflect.TypeOf(Config{})
+	node := tree
+	for i, seg := range path {
+		if seg == "" {
+			return fmt.Errorf("empty path segment")
+		}
+
+		//||------------------------------------------------------------------------------------------------||
+		//|| Resolve segment against the schema (adapters use their registered type, else free-form)
+		//||------------------------------------------------------------------------------------------------||
+
+		if t == rawMessageType {
+			t = anyType
+			if at, ok := adapterType(path[i-1]); ok {
+				t = at
+			}
+		}
+		switch t.Kind() {
+		case reflect.Struct:
+			f, ok := fieldByJSONName(t, seg)
+			if !ok {
+				return fmt.Errorf("unknown field %q", strings.Join(path[:i+1], "."))
+			}
+			seg, t = jsonName(f), f.Type
+		case reflect.Map:
+			seg, t = matchKey(node, seg), t.Elem()
+		case reflect.Interface:
+			seg = matchKey(node, seg)
+		default:
+			return fmt.Errorf("%q is not an object", strings.Join(path[:i], "."))
+		}
+
+		//||------------------------------------------------------------------------------------------------||
+		//|| Leaf: coerce and store
+		//||------------------------------------------------------------------------------------------------||
+
+		if i == len(path)-1 {
+			v, err := coerce(t, value)
+			if err != nil {
+				return fmt.Errorf("%s: %w", strings.Join(path, "."), err)
+			}
+			node[seg] = v
+			return nil
+		}
+		child, ok := node[seg].(map[string]any)
+		if !ok {
+			child = map[string]any{}
+			node[seg] = child
+		}
+		node = child
+	}
+	return nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| coerce: string -> value of kind t (lists accept JSON or comma-separated)
+//||------------------------------------------------------------------------------------------------||
+
+func coerce(t reflect.Type, value string) (any, error) {
+	switch t.Kind() {
+	case reflect.String:
+		return value, nil
+	case reflect.Bool:
+		return strconv.ParseBool(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(value, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(value, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(value, 64)
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.String && !strings.HasPrefix(strings.TrimSpace(value), "[") {
+			parts := strings.Split(value, ",")
+			for i := range parts {
+				parts[i] = strings.TrimSpace(parts[i])
+			}
+			return parts, nil
+		}
+	}
+	var v any
+	if t.Kind() == reflect.Interface && json.Unmarshal([]byte(value), &v) != nil {
+		return value, nil // free-form adapter field: plain strings stay strings
+	}
+	if err := json.Unmarshal([]byte(value), &v); err != nil {
+		return nil, fmt.Errorf("want JSON for %s: %w", t, err)
+	}
+	return v, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Schema Helpers
+//||------------------------------------------------------------------------------------------------||
+
+func isSection(name string) bool {
+	_, ok := fieldByJSONName(reflect.TypeOf(Config{}), name)
+	return ok
+}
+
+func jsonName(f reflect.StructField) string {
+	if name, _, _ := strings.Cut(f.Tag.Get("json"), ","); name != "" && name != "-" {
+		return name
+	}
+	return f.Name
+}
+
+func fieldByJSONName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.IsExported() && f.Tag.Get("json") != "-" && strings.EqualFold(jsonName(f), name) {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// matchKey returns the existing key equal to name ignoring case (env vars are upper-case), else name.
+func matchKey(node map[string]any, name string) string {
+	if _, ok := node[name]; ok {
+		return name
+	}
+	for k := range node {
+		if strings.EqualFold(k, name) {
+			return k
+		}
+	}
+	return name
+}
@@ -0,0 +1,173 @@
+//||------------------------------------------------------------------------------------------------||
+//|| Config Package: JSON Schema (generated from the Config structs)
+//|| schema.go
+//||------------------------------------------------------------------------------------------------||
+
+package config
+
+//||------------------------------------------------------------------------------------------------||
+//|| Import
+//||------------------------------------------------------------------------------------------------||
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Schema Hints: what reflection cannot see (required fields and enums)
+//||
+//|| The loader matches these names case-insensitively, so each enum also lists the upper and
+//|| title case spellings (see enumVariants); aliases that normalize() rewrites are listed too.
+//||------------------------------------------------------------------------------------------------||
+
+var schemaRequired = map[reflect.Type][]string{
+	reflect.TypeOf(Config{}):                {"app"},
+	reflect.TypeOf(AppConfig{}):             {"name", "port"},
+	reflect.TypeOf(DBInstanceConfig{}):      {"driver"},
+	reflect.TypeOf(CacheInstanceConfig{}):   {"backend"},
+	reflect.TypeOf(StorageInstanceConfig{}): {"backend"},
+	reflect.TypeOf(QueueInstanceConfig{}):   {"backend"},
+	reflect.TypeOf(HTTPInstanceConfig{}):    {"backend", "port"},
+}
+
+func schemaEnum(t reflect.Type, field string) []string {
+	switch t.Name() + "." + field {
+	case "DBInstanceConfig.driver":
+		return Backends(SectionDB)
+	case "CacheInstanceConfig.backend":
+		return Backends(SectionCache)
+	case "CacheInstanceConfig.mode":
+		return []string{"single", "sentinel", "cluster"}
+	case "CacheInstanceConfig.eviction":
+		return []string{"lru", "lfu"}
+	case "StorageInstanceConfig.backend":
+		return Backends(SectionStorage)
+	case "QueueInstanceConfig.backend":
+		return Backends(SectionQueue)
+	case "HTTPInstanceConfig.backend":
+		return []string{"mux", "http", "servemux", "nethttp"}
+	}
+	return nil
+}
+
+// enumVariants is values followed by their upper and title case spellings, without duplicates.
+func enumVariants(values []string) []string {
+	out := make([]string, 0, 3*len(values))
+	seen := map[string]bool{}
+	add := func(v string) {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	for _, v := range values {
+		add(v)
+	}
+	for _, v := range values {
+		add(strings.ToUpper(v))
+	}
+	for _, v := range values {
+		if v != "" {
+			add(strings.ToUpper(v[:1]) + v[1:])
+		}
+	}
+	return out
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| JSONSchema: draft 2020-12 document for the config file (enums reflect registered backends)
+//||
+//|| config.schema.json at the repo root is this document with every subsystem linked; refresh it
+//|| with `go generate ./config` (the app tests fail when it is stale).
+//||------------------------------------------------------------------------------------------------||
+
+//go:generate go run ../internal/schemagen -o ../config.schema.json
+
+func JSONSchema() map[string]any {
+	s := schemaFor(reflect.TypeOf(Config{}))
+	s["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	s["title"] = "Aria configuration"
+	props := s["properties"].(map[string]any)
+	props["$schema"] = map[string]any{"type": "string"} // lets editors point at this document
+	props["adapters"] = adaptersSchema()
+	return s
+}
+
+// JSONSchemaJSON is JSONSchema, indented.
+func JSONSchemaJSON() ([]byte, error) {
+	return json.MarshalIndent(JSONSchema(), "", "  ")
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| schemaFor: reflect one type
+//||------------------------------------------------------------------------------------------------||
+
+func schemaFor(t reflect.Type) map[string]any {
+	if t == rawMessageType {
+		return map[string]any{}
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		props := map[string]any{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() || f.Tag.Get("json") == "-" {
+				continue
+			}
+			name := jsonName(f)
+			p := schemaFor(f.Type)
+			if enum := schemaEnum(t, name); len(enum) > 0 {
+				p["enum"] = enumVariants(enum)
+			}
+			props[name] = p
+		}
+		s := map[string]any{"type": "object", "properties": props, "additionalProperties": false}
+		if req := schemaRequired[t]; len(req) > 0 {
+			s["required"] = req
+		}
+		return s
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaFor(t.Elem())}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": schemaFor(t.Elem())}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Ptr:
+		return schemaFor(t.Elem())
+	}
+	return map[string]any{}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| adaptersSchema: registered adapters are typed, anything else is free-form
+//||------------------------------------------------------------------------------------------------||
+
+func adaptersSchema() map[string]any {
+	props := map[string]any{}
+	for _, name := range Adapters() {
+		t, _ := adapterType(name)
+		props[name] = schemaFor(t)
+	}
+	return map[string]any{"type": "object", "properties": props, "additionalProperties": map[string]any{"type": "object"}}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| stripSchemaKey: "$schema" is for editors only; drop it before strict decoding
+//||------------------------------------------------------------------------------------------------||
+
+func stripSchemaKey(tree map[string]any) {
+	for k := range tree {
+		if strings.EqualFold(k, "$schema") {
+			delete(tree, k)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+//||------------------------------------------------------------------------------------------------||
+//|| Config Package: Sealed Secrets File (AES-256-GCM, key derived from a master key with argon2id)
+//|| sealed.go
+//||------------------------------------------------------------------------------------------------||
+
+package config
+
+//||------------------------------------------------------------------------------------------------||
+//|| Import
+//||------------------------------------------------------------------------------------------------||
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Defaults
+//||------------------------------------------------------------------------------------------------||
+
+const (
+	EnvSecretsFile = "ARIA_SECRETS_FILE"
+	EnvMasterKey   = "ARIA_MASTER_KEY"
+	sealedVersion  = 1
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| sealedFile: on-disk format ([]byte fields are base64 in JSON)
+//||------------------------------------------------------------------------------------------------||
+
+type sealedFile struct {
+	Version int    `json:"version"`
+	KDF     string `json:"kdf"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| SealSecrets: encrypt values into path (0600), replacing it
+//||------------------------------------------------------------------------------------------------||
+
+func SealSecrets(path string, masterKey []byte, values map[string]string) error {
+	if len(masterKey) == 0 {
+		return fmt.Errorf("master key is empty")
+	}
+	plain, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	f := sealedFile{Version: sealedVersion, KDF: "argon2id", Salt: make([]byte, 16)}
+	if _, err := rand.Read(f.Salt); err != nil {
+		return err
+	}
+	gcm, err := sealedCipher(masterKey, f.Salt)
+	if err != nil {
+		return err
+	}
+	f.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(f.Nonce); err != nil {
+		return err
+	}
+	f.Data = gcm.Seal(nil, f.Nonce, plain, nil)
+
+	out, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, 0o600)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| OpenSealed: decrypt path with masterKey
+//||------------------------------------------------------------------------------------------------||
+
+func OpenSealed(path string, masterKey []byte) (map[string]string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f sealedFile
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil, fmt.Errorf("%s: not a sealed secrets file: %w", path, err)
+	}
+	if f.Version != sealedVersion || f.KDF != "argon2id" {
+		return nil, fmt.Errorf("%s: unsupported sealed format v%d/%s", path, f.Version, f.KDF)
+	}
+	gcm, err := sealedCipher(masterKey, f.Salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(f.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("%s: bad nonce", path)
+	}
+	plain, err := gcm.Open(nil, f.Nonce, f.Data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: wrong master key or corrupted file", path)
+	}
+	values := map[string]string{}
+	if err := json.Unmarshal(plain, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func sealedCipher(masterKey, salt []byte) (cipher.AEAD, error) {
+	key := argon2.IDKey(masterKey, salt, 1, 64*1024, 4, 32)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| SealedFileProvider: sealed://<key> (Path/MasterKey default to $ARIA_SECRETS_FILE/$ARIA_MASTER_KEY)
+//||
+//|| The decrypted file is cached until its modification time changes.
+//||------------------------------------------------------------------------------------------------||
+
+type SealedFileProvider struct {
+	Path      string
+	MasterKey []byte
+
+	mu      sync.Mutex
+	cached  map[string]string
+	srcPath string
+	modTime time.Time
+}
+
+func (p *SealedFileProvider) Resolve(ref SecretRef) (string, error) {
+	path, key := p.Path, p.MasterKey
+	if path == "" {
+		path = os.Getenv(EnvSecretsFile)
+	}
+	if len(key) == 0 {
+		key = []byte(os.Getenv(EnvMasterKey))
+	}
+	if path == "" || len(key) == 0 {
+		return "", fmt.Errorf("sealed secrets need a file and master key (%s, %s)", EnvSecretsFile, EnvMasterKey)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cached == nil || p.srcPath != path || !p.modTime.Equal(info.ModTime()) {
+		values, err := OpenSealed(path, key)
+		if err != nil {
+			return "", err
+		}
+		p.cached, p.srcPath, p.modTime = values, path, info.ModTime()
+	}
+	name := ref.Path
+	if ref.Key != "" {
+		name = ref.Key
+	}
+	return lookupKey(p.cached, name)
+}
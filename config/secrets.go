@@ -0,0 +1,280 @@
+//||------------------------------------------------------------------------------------------------||
+//|| Config Package: Secret References
+//|| secrets.go
+//||------------------------------------------------------------------------------------------------||
+
+package config
+
+//||------------------------------------------------------------------------------------------------||
+//|| Import
+//||------------------------------------------------------------------------------------------------||
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| SecretRef: "<scheme>://<path>[#<key>]"
+//||
+//||   file:///run/secrets/db            whole file (trailing newline trimmed)
+//||   file:///run/secrets/app.json#db   one key of a JSON object file
+//||   env://DB_PASSWORD                 environment variable
+//||   sealed://db_pass                  local encrypted secrets file (ARIA_SECRETS_FILE, ARIA_MASTER_KEY)
+//||   vault://kv/aria#db_pass           Vault KV v2: mount "kv", path "aria", key "db_pass" (VAULT_ADDR, VAULT_TOKEN)
+//||
+//|| config.secrets.sample.json shows every secret of the sample config written as a reference.
+//||------------------------------------------------------------------------------------------------||
+
+type SecretRef struct {
+	Scheme string
+	Path   string
+	Key    string
+}
+
+func (r SecretRef) String() string {
+	s := r.Scheme + "://" + r.Path
+	if r.Key != "" {
+		s += "#" + r.Key
+	}
+	return s
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| SecretProvider: resolves references for one scheme
+//||------------------------------------------------------------------------------------------------||
+
+type SecretProvider interface {
+	Resolve(ref SecretRef) (string, error)
+}
+
+// SecretProviderFunc adapts a function to SecretProvider.
+type SecretProviderFunc func(ref SecretRef) (string, error)
+
+func (f SecretProviderFunc) Resolve(ref SecretRef) (string, error) { return f(ref) }
+
+//||------------------------------------------------------------------------------------------------||
+//|| Registry
+//||------------------------------------------------------------------------------------------------||
+
+var (
+	secretsMu sync.RWMutex
+	secrets   = map[string]SecretProvider{}
+)
+
+// RegisterSecretProvider adds (or replaces) the provider for scheme; schemes are case-insensitive.
+func RegisterSecretProvider(scheme string, p SecretProvider) {
+	if scheme == "" || p == nil {
+		panic("config: RegisterSecretProvider requires a scheme and provider")
+	}
+	secretsMu.Lock()
+	secrets[strings.ToLower(scheme)] = p
+	secretsMu.Unlock()
+}
+
+// SecretProviders returns the registered schemes (sorted).
+func SecretProviders() []string {
+	secretsMu.RLock()
+	defer secretsMu.RUnlock()
+	out := make([]string, 0, len(secrets))
+	for scheme := range secrets {
+		out = append(out, scheme)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func init() {
+	RegisterSecretProvider("file", SecretProviderFunc(resolveFile))
+	RegisterSecretProvider("env", SecretProviderFunc(resolveEnv))
+	RegisterSecretProvider("sealed", &SealedFileProvider{})
+	RegisterSecretProvider("vault", &VaultProvider{})
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| ResolveSecret: value itself unless it is a reference to a registered scheme
+//||
+//|| Unregistered schemes pass through untouched, so URIs like mongodb://... are never resolved.
+//||------------------------------------------------------------------------------------------------||
+
+func ResolveSecret(value string) (string, error) {
+	scheme, rest, ok := strings.Cut(value, "://")
+	if !ok || scheme == "" {
+		return value, nil
+	}
+	secretsMu.RLock()
+	p, ok := secrets[strings.ToLower(scheme)]
+	secretsMu.RUnlock()
+	if !ok {
+		return value, nil
+	}
+	ref := SecretRef{Scheme: strings.ToLower(scheme)}
+	ref.Path, ref.Key, _ = strings.Cut(rest, "#")
+	secret, err := p.Resolve(ref)
+	if err != nil {
+		return "", fmt.Errorf("resolve %s: %w", ref, err)
+	}
+	return secret, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| resolveSecrets: every secret-bearing field (runs after expandEnvStrings)
+//||------------------------------------------------------------------------------------------------||
+
+func resolveSecrets(c *Config) error {
+	var errs []string
+	resolve := func(field string, v *string) {
+		s, err := ResolveSecret(*v)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", field, err))
+			return
+		}
+		*v = s
+	}
+
+	// DB
+	for k, v := range c.DB {
+		resolve("db."+k+".password", &v.Password)
+		resolve("db."+k+".uri", &v.URI)
+		c.DB[k] = v
+	}
+
+	// Cache
+	for k, v := range c.Cache {
+		resolve("cache."+k+".password", &v.Password)
+		resolve("cache."+k+".sentinel_password", &v.SentinelPassword)
+		c.Cache[k] = v
+	}
+
+	// Storage
+	for k, v := range c.Storage {
+		resolve("storage."+k+".access_key", &v.AccessKey)
+		resolve("storage."+k+".secret_key", &v.SecretKey)
+		resolve("storage."+k+".credentials", &v.CredentialsJSON)
+		resolve("storage."+k+".sign_key", &v.SignKey)
+		c.Storage[k] = v
+	}
+
+	// Queue
+	for k, v := range c.Queue {
+		resolve("queue."+k+".password", &v.Password)
+		c.Queue[k] = v
+	}
+
+	// Auth
+	resolve("auth.pepper", &c.Auth.Pepper)
+	resolve("auth.csrf", &c.Auth.CSRF)
+	resolve("auth.jwt_secret", &c.Auth.JWTSecret)
+
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return fmt.Errorf("secrets: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| file://
+//||------------------------------------------------------------------------------------------------||
+
+func resolveFile(ref SecretRef) (string, error) {
+	data, err := os.ReadFile(ref.Path)
+	if err != nil {
+		return "", err
+	}
+	if ref.Key == "" {
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+	var values map[string]string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return "", fmt.Errorf("%s is not a JSON object of strings: %w", ref.Path, err)
+	}
+	return lookupKey(values, ref.Key)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| env://
+//||------------------------------------------------------------------------------------------------||
+
+func resolveEnv(ref SecretRef) (string, error) {
+	v, ok := os.LookupEnv(ref.Path)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s not set", ref.Path)
+	}
+	return v, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| vault:// (KV v2 over HTTP; Addr/Token default to VAULT_ADDR/VAULT_TOKEN)
+//||------------------------------------------------------------------------------------------------||
+
+type VaultProvider struct {
+	Addr   string
+	Token  string
+	Client *http.Client
+}
+
+func (v *VaultProvider) Resolve(ref SecretRef) (string, error) {
+	addr, token := v.Addr, v.Token
+	if addr == "" {
+		addr = os.Getenv("VAULT_ADDR")
+	}
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+	if addr == "" || token == "" {
+		return "", fmt.Errorf("vault address/token not configured (VAULT_ADDR, VAULT_TOKEN)")
+	}
+	mount, path, ok := strings.Cut(strings.Trim(ref.Path, "/"), "/")
+	if !ok || ref.Key == "" {
+		return "", fmt.Errorf("want vault://<mount>/<path>#<key>")
+	}
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimRight(addr, "/")+"/v1/"+mount+"/data/"+path, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	client := v.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("vault %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var out struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("decode vault response: %w", err)
+	}
+	return lookupKey(out.Data.Data, ref.Key)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Helpers
+//||------------------------------------------------------------------------------------------------||
+
+func lookupKey(values map[string]string, key string) (string, error) {
+	v, ok := values[key]
+	if !ok {
+		return "", fmt.Errorf("key %q not found", key)
+	}
+	return v, nil
+}
@@ -5,6 +5,12 @@
 
 package config
 
+//||------------------------------------------------------------------------------------------------||
+//|| Import
+//||------------------------------------------------------------------------------------------------||
+
+import "encoding/json"
+
 //||------------------------------------------------------------------------------------------------||
 //|| Config: Root Struct
 //||------------------------------------------------------------------------------------------------||
@@ -19,6 +25,7 @@ type Config struct {
 	Auth     AuthConfig                       `json:"auth"`
 	Locale   LocaleConfig                     `json:"locale"`
 	Template TemplateConfig                   `json:"template"`
+	Adapters map[string]json.RawMessage       `json:"adapters,omitempty"` // see RegisterAdapter / Adapter
 }
 
 //||------------------------------------------------------------------------------------------------||
@@ -46,6 +53,7 @@ type DBInstanceConfig struct {
 	Database string `json:"database,omitempty"`
 	SSLMode  string `json:"sslmode,omitempty"` // postgres only
 	URI      string `json:"uri,omitempty"`     // optional mongo URI
+	Lazy     bool   `json:"lazy,omitempty"`    // connect on first use instead of at startup
 }
 
 //||------------------------------------------------------------------------------------------------||
@@ -53,12 +61,37 @@ type DBInstanceConfig struct {
 //||------------------------------------------------------------------------------------------------||
 
 type CacheInstanceConfig struct {
-	Backend  string   `json:"backend"` // redis | keydb | memcached | memory
-	Host     string   `json:"host,omitempty"`
-	Port     int      `json:"port,omitempty"`
-	Password string   `json:"password,omitempty"`
-	DB       int      `json:"db,omitempty"`
-	Servers  []string `json:"servers,omitempty"` // memcached
+	Backend          string   `json:"backend"`        // redis | keydb | memcached | memory | layered
+	Mode             string   `json:"mode,omitempty"` // redis: single | sentinel | cluster
+	Host             string   `json:"host,omitempty"`
+	Port             int      `json:"port,omitempty"`
+	Username         string   `json:"username,omitempty"`
+	Password         string   `json:"password,omitempty"`
+	DB               int      `json:"db,omitempty"`
+	Servers          []string `json:"servers,omitempty"`           // memcached, redis cluster nodes
+	MasterName       string   `json:"master_name,omitempty"`       // redis sentinel
+	SentinelAddrs    []string `json:"sentinel_addrs,omitempty"`    // redis sentinel
+	SentinelPassword string   `json:"sentinel_password,omitempty"` // redis sentinel
+	TLS              bool     `json:"tls,omitempty"`
+	TLSCertFile      string   `json:"tls_cert,omitempty"`
+	TLSKeyFile       string   `json:"tls_key,omitempty"`
+	TLSCAFile        string   `json:"tls_ca,omitempty"`
+	TLSServerName    string   `json:"tls_server_name,omitempty"`
+	TLSSkipVerify    bool     `json:"tls_skip_verify,omitempty"`
+	PoolSize         int      `json:"pool_size,omitempty"`
+	MinIdleConns     int      `json:"min_idle_conns,omitempty"`
+	MaxRetries       int      `json:"max_retries,omitempty"`
+	DialTimeout      int      `json:"dial_timeout,omitempty"`   // seconds
+	PoolTimeout      int      `json:"pool_timeout,omitempty"`   // seconds
+	MaxEntries       int      `json:"max_entries,omitempty"`    // memory: 0 = unbounded
+	MaxBytes         int64    `json:"max_bytes,omitempty"`      // memory: 0 = unbounded
+	Eviction         string   `json:"eviction,omitempty"`       // memory: lru | lfu
+	Shards           int      `json:"shards,omitempty"`         // memory: lock shards (default 16)
+	SweepInterval    int      `json:"sweep_interval,omitempty"` // memory: seconds between expiry sweeps
+	L2               string   `json:"l2,omitempty"`             // layered: name of the redis/keydb cache
+	L1TTL            int      `json:"l1_ttl,omitempty"`         // layered: seconds an entry lives in memory
+	Channel          string   `json:"channel,omitempty"`        // layered: pub/sub invalidation channel
+	Lazy             bool     `json:"lazy,omitempty"`           // connect on first use instead of at startup
 }
 
 //||------------------------------------------------------------------------------------------------||
@@ -75,6 +108,13 @@ type StorageInstanceConfig struct {
 	SecretKey       string `json:"secret_key,omitempty"`
 	Endpoint        string `json:"endpoint,omitempty"`
 	Dir             string `json:"dir,omitempty"`
+	PublicURL       string `json:"public_url,omitempty"`        // local: base URL of the presigned-URL handler
+	SignKey         string `json:"sign_key,omitempty"`          // local: HMAC key for presigned URLs
+	ProbeWrite      bool   `json:"probe_write,omitempty"`       // ping writes/reads/deletes a test object
+	Timeout         int    `json:"timeout,omitempty"`           // seconds per call; streams: to open, or without progress (0 = 60)
+	MaxRetries      int    `json:"max_retries,omitempty"`       // retries on throttling/5xx/network errors (0 = off)
+	RetryBackoff    int    `json:"retry_backoff,omitempty"`     // milliseconds before the first retry, doubled each time (0 = 100)
+	RetryMaxBackoff int    `json:"retry_max_backoff,omitempty"` // milliseconds cap per wait (0 = 5000)
 }
 
 //||------------------------------------------------------------------------------------------------||
@@ -88,6 +128,7 @@ type QueueInstanceConfig struct {
 	User     string `json:"user,omitempty"`
 	Password string `json:"password,omitempty"`
 	Vhost    string `json:"vhost,omitempty"`
+	Lazy     bool   `json:"lazy,omitempty"` // connect on first use, reconnect with backoff
 }
 
 //||------------------------------------------------------------------------------------------------||
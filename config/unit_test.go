@@ -10,11 +10,19 @@ package config
 //||------------------------------------------------------------------------------------------------||
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 )
 
 //||------------------------------------------------------------------------------------------------||
@@ -224,3 +232,449 @@ func TestDBUnsupportedDriver(t *testing.T) {
 		t.Fatal("expected error for unsupported db driver, got nil")
 	}
 }
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Registered Backends
+//||------------------------------------------------------------------------------------------------||
+
+func TestRegisteredBackendValidation(t *testing.T) {
+	cfg := func(backend string) *Config {
+		return &Config{
+			App:   AppConfig{Name: "a", Env: "d", Port: 8080},
+			Cache: map[string]CacheInstanceConfig{"c": {Backend: backend}},
+		}
+	}
+
+	RegisterBackend(SectionCache, "Dragonfly")
+	t.Cleanup(func() {
+		backendsMu.Lock()
+		delete(backends, SectionCache)
+		backendsMu.Unlock()
+	})
+	if err := Validate(cfg("dragonfly")); err != nil {
+		t.Fatalf("registered backend rejected: %v", err)
+	}
+	err := Validate(cfg("hazelcast"))
+	if err == nil || !strings.Contains(err.Error(), "cache.c.backend") {
+		t.Fatalf("expected cache backend error, got %v", err)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Layered Load: yaml base -> env json -> ARIA_ env vars -> overrides
+//||------------------------------------------------------------------------------------------------||
+
+func TestLoad_Layers(t *testing.T) {
+	tmp := t.TempDir()
+	base := writeJSON(t, tmp, "config.yaml", `
+app:
+  name: aria
+  env: staging
+  port: 8080
+db:
+  main:
+    driver: postgres
+    host: localhost
+    port: 5432
+    database: aria
+    password: base-secret
+`)
+	writeJSON(t, tmp, "config.staging.json", `{"db":{"main":{"host":"staging-db","user":"stage"}}}`)
+	t.Setenv("ARIA_DB__MAIN__HOST", "env-db")
+	t.Setenv("ARIA_DB__MAIN__PORT", "6432")
+	t.Setenv("ARIA_UNRELATED", "ignored")
+
+	cfg, err := Load(LoadOptions{Path: base, Overrides: []string{"app.port=9090", "locale.supported=en-US, fr-FR"}})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	main := cfg.DB["main"]
+	if main.Host != "env-db" || main.Port != 6432 || main.User != "stage" || main.Password != "base-secret" {
+		t.Fatalf("db.main = %+v", main)
+	}
+	if cfg.App.Port != 9090 {
+		t.Fatalf("app.port = %d, want override 9090", cfg.App.Port)
+	}
+	if strings.Join(cfg.Locale.Supported, "|") != "en-US|fr-FR" {
+		t.Fatalf("locale.supported = %v", cfg.Locale.Supported)
+	}
+
+	t.Setenv("ARIA_DB__MAIN__HOTS", "typo")
+	if _, err := Load(LoadOptions{Path: base}); err == nil || !strings.Contains(err.Error(), "db.main.hots") {
+		t.Fatalf("expected unknown field error, got %v", err)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test TOML + unknown extension
+//||------------------------------------------------------------------------------------------------||
+
+func TestLoad_TOML(t *testing.T) {
+	tmp := t.TempDir()
+	path := writeJSON(t, tmp, "config.toml", `
+[app]
+name = "aria"
+env = "dev"
+port = 8080
+
+[queue.main]
+backend = "rabbitmq"
+host = "localhost"
+port = 5672
+lazy = true
+`)
+	cfg, err := Load(LoadOptions{Path: path, NoEnvVars: true})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if q := cfg.Queue["main"]; q.Port != 5672 || !q.Lazy {
+		t.Fatalf("queue.main = %+v", q)
+	}
+
+	bad := writeJSON(t, tmp, "config.ini", "x=1")
+	if _, err := Load(LoadOptions{Path: bad}); err == nil {
+		t.Fatal("expected unsupported format error")
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Watch: file change swaps config and notifies only matching subscribers
+//||------------------------------------------------------------------------------------------------||
+
+func TestWatch_ReloadAndOnChange(t *testing.T) {
+	t.Cleanup(Reset)
+
+	tmp := t.TempDir()
+	body := `{"app":{"name":"a","env":"d","port":%d},"db":{"main":{"driver":"postgres","host":"localhost","database":"d"}}}`
+	path := writeJSON(t, tmp, "config.json", fmt.Sprintf(body, 1001))
+	if _, err := InitWithOptions(LoadOptions{Path: path, NoEnvVars: true}); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	appChanged := make(chan int, 1)
+	cancelApp := OnChange("app", func(old, next *Config) { appChanged <- next.App.Port })
+	defer cancelApp()
+	defer OnChange("db", func(old, next *Config) { t.Error("db subscriber called for an app-only change") })()
+
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	if err := Watch(ctx); err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	writeJSON(t, tmp, "config.json", fmt.Sprintf(body, 2002))
+	select {
+	case port := <-appChanged:
+		if port != 2002 || GetConfig().App.Port != 2002 {
+			t.Fatalf("port = %d, GetConfig = %d", port, GetConfig().App.Port)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("no reload after file change")
+	}
+
+	// an invalid file is rejected and the current config kept
+	writeJSON(t, tmp, "config.json", fmt.Sprintf(body, 70000))
+	if err := Reload(); err == nil {
+		t.Fatal("expected validation error on reload")
+	}
+	if GetConfig().App.Port != 2002 {
+		t.Fatalf("config swapped despite invalid reload: %d", GetConfig().App.Port)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Secrets: file, env, sealed and vault references; other URIs pass through
+//||------------------------------------------------------------------------------------------------||
+
+func TestLoad_SecretReferences(t *testing.T) {
+	tmp := t.TempDir()
+	writeJSON(t, tmp, "db_pass", "from-file\n")
+	sealedPath := filepath.Join(tmp, "secrets.sealed")
+	if err := SealSecrets(sealedPath, []byte("master"), map[string]string{"s3_secret": "from-sealed"}); err != nil {
+		t.Fatalf("SealSecrets: %v", err)
+	}
+	if _, err := OpenSealed(sealedPath, []byte("wrong")); err == nil {
+		t.Fatal("OpenSealed accepted a wrong master key")
+	}
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/kv/data/aria" || r.Header.Get("X-Vault-Token") != "tok" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"data":{"mq_pass":"from-vault"}}}`)
+	}))
+	defer vault.Close()
+
+	t.Setenv("TEST_CACHE_PASS", "from-env")
+	t.Setenv(EnvSecretsFile, sealedPath)
+	t.Setenv(EnvMasterKey, "master")
+	t.Setenv("VAULT_ADDR", vault.URL)
+	t.Setenv("VAULT_TOKEN", "tok")
+
+	path := writeJSON(t, tmp, "config.json", `{
+	  "app":{"name":"a","env":"d","port":8080},
+	  "db":{"main":{"driver":"postgres","host":"localhost","database":"d","password":"file://`+filepath.ToSlash(filepath.Join(tmp, "db_pass"))+`"},
+	        "docs":{"driver":"mongo","host":"mongodb://localhost:27017","database":"d","uri":"mongodb://localhost:27017"}},
+	  "cache":{"main":{"backend":"redis","host":"localhost","password":"env://TEST_CACHE_PASS"}},
+	  "storage":{"assets":{"backend":"s3","bucket":"b","region":"r","access_key":"k","secret_key":"sealed://s3_secret"}},
+	  "queue":{"main":{"backend":"rabbitmq","host":"localhost","password":"vault://kv/aria#mq_pass"}}
+	}`)
+	cfg, err := Load(LoadOptions{Path: path, NoEnvVars: true})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	got := []string{cfg.DB["main"].Password, cfg.DB["docs"].URI, cfg.Cache["main"].Password, cfg.Storage["assets"].SecretKey, cfg.Queue["main"].Password}
+	want := []string{"from-file", "mongodb://localhost:27017", "from-env", "from-sealed", "from-vault"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("resolved = %v, want %v", got, want)
+	}
+
+	t.Setenv("TEST_CACHE_PASS", "")
+	os.Unsetenv("TEST_CACHE_PASS")
+	if _, err := Load(LoadOptions{Path: path, NoEnvVars: true}); err == nil || !strings.Contains(err.Error(), "cache.main.password") {
+		t.Fatalf("expected unresolved secret error, got %v", err)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Validate: every problem reported at once, by JSON path
+//||------------------------------------------------------------------------------------------------||
+
+func TestValidate_AllProblems(t *testing.T) {
+	c := &Config{
+		App:     AppConfig{Name: "a", Port: 8080},
+		Storage: map[string]StorageInstanceConfig{"assets": {Backend: "s3", Region: "us-west-2", MaxRetries: -1}},
+		Cache: map[string]CacheInstanceConfig{
+			"l1":  {Backend: "layered", L2: "mem"},
+			"mem": {Backend: "memory", Eviction: "random"},
+		},
+		Queue: map[string]QueueInstanceConfig{"main": {Backend: "rabbitmq", Port: 70000}},
+	}
+	err := Validate(c)
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Validate = %v, want *ValidationError", err)
+	}
+	want := []string{
+		`cache.l1.l2 cache "mem" must be redis or keydb, not "memory"`,
+		`cache.mem.eviction unsupported: "random" (use lru or lfu)`,
+		`queue.main.host required for rabbitmq`,
+		`queue.main.port out of range: 70000`,
+		`storage.assets.bucket required for s3`,
+		`storage.assets.max_retries must not be negative: -1`,
+	}
+	var got []string
+	for _, p := range verr.Problems {
+		got = append(got, p.String())
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("problems:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test JSON Schema: generated from the structs; "$schema" key accepted by the loader
+//||------------------------------------------------------------------------------------------------||
+
+func TestJSONSchema(t *testing.T) {
+	raw, err := JSONSchemaJSON()
+	if err != nil {
+		t.Fatalf("JSONSchemaJSON: %v", err)
+	}
+	var doc struct {
+		Properties map[string]struct {
+			Properties           map[string]map[string]any `json:"properties"`
+			AdditionalProperties json.RawMessage           `json:"additionalProperties"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		t.Fatalf("decode schema: %v", err)
+	}
+	if doc.Properties["auth"].Properties["jwt_secret"]["type"] != "string" {
+		t.Fatalf("auth.jwt_secret missing from schema: %s", raw)
+	}
+	if !strings.Contains(string(doc.Properties["db"].AdditionalProperties), `"postgres"`) {
+		t.Fatalf("db driver enum missing: %s", doc.Properties["db"].AdditionalProperties)
+	}
+	// every spelling the loader accepts is in the enum
+	for _, want := range []string{`"POSTGRES"`, `"Postgres"`} {
+		if !strings.Contains(string(doc.Properties["db"].AdditionalProperties), want) {
+			t.Fatalf("db driver enum lacks %s: %s", want, doc.Properties["db"].AdditionalProperties)
+		}
+	}
+	for _, want := range []string{`"servemux"`, `"nethttp"`, `"HTTP"`} {
+		if !strings.Contains(string(doc.Properties["http"].AdditionalProperties), want) {
+			t.Fatalf("http backend enum lacks %s: %s", want, doc.Properties["http"].AdditionalProperties)
+		}
+	}
+
+	path := writeJSON(t, t.TempDir(), "config.json", `{"$schema":"./config.schema.json","app":{"name":"a","port":8080}}`)
+	if _, err := Load(LoadOptions{Path: path, NoEnvVars: true}); err != nil {
+		t.Fatalf("Load with $schema: %v", err)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Adapters: typed registration, env expansion/overrides, validation and retrieval
+//||------------------------------------------------------------------------------------------------||
+
+type testStripeConfig struct {
+	APIKey        string `json:"api_key"`
+	WebhookSecret string `json:"webhook_secret"`
+	Retries       int    `json:"retries"`
+}
+
+func (s testStripeConfig) Validate() error {
+	if s.APIKey == "" {
+		return &ValidationError{Problems: []Problem{{Path: "api_key", Message: "required"}}}
+	}
+	return nil
+}
+
+func TestAdapters(t *testing.T) {
+	RegisterAdapter[testStripeConfig]("stripe")
+	t.Cleanup(func() {
+		adaptersMu.Lock()
+		delete(adapters, "stripe")
+		adaptersMu.Unlock()
+	})
+	t.Setenv("STRIPE_WEBHOOK", "whsec_x")
+	t.Setenv("ARIA_ADAPTERS__STRIPE__RETRIES", "3")
+	dir := t.TempDir()
+
+	path := writeJSON(t, dir, "config.json", `{
+		"app": {"name": "a", "port": 8080},
+		"adapters": {
+			"stripe": {"api_key": "sk_test", "webhook_secret": "${STRIPE_WEBHOOK}"},
+			"other":  {"anything": ["goes"]}
+		}
+	}`)
+	c, err := Load(LoadOptions{Path: path})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	s, err := Adapter[testStripeConfig](c, "stripe")
+	if err != nil {
+		t.Fatalf("Adapter: %v", err)
+	}
+	if s.APIKey != "sk_test" || s.WebhookSecret != "whsec_x" || s.Retries != 3 {
+		t.Fatalf("stripe = %+v", s)
+	}
+	if _, err := Adapter[struct{ APIKey string }](c, "stripe"); err == nil {
+		t.Fatal("Adapter with the wrong type should fail")
+	}
+	if _, err := Adapter[testStripeConfig](c, "paypal"); err == nil {
+		t.Fatal("Adapter for a missing block should fail")
+	}
+
+	path = writeJSON(t, dir, "bad.json", `{
+		"app": {"name": "a", "port": 8080},
+		"adapters": {"stripe": {"webhook_secret": "x"}, "Stripe2": {}}
+	}`)
+	if _, err := Load(LoadOptions{Path: path, NoEnvVars: true}); err == nil || !strings.Contains(err.Error(), "adapters.stripe.api_key required") {
+		t.Fatalf("Load invalid adapter = %v", err)
+	}
+	path = writeJSON(t, dir, "unknown.json", `{
+		"app": {"name": "a", "port": 8080},
+		"adapters": {"stripe": {"api_key": "k", "typo": 1}}
+	}`)
+	if _, err := Load(LoadOptions{Path: path, NoEnvVars: true}); err == nil || !strings.Contains(err.Error(), `adapters.stripe json: unknown field "typo"`) {
+		t.Fatalf("Load unknown adapter field = %v", err)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Sample: the shipped config.sample.json loads as-is (no secret stores, no env vars)
+//||------------------------------------------------------------------------------------------------||
+
+func TestLoad_SampleConfig(t *testing.T) {
+	cfg, err := Load(LoadOptions{Path: filepath.Join("..", "config.sample.json"), NoEnvVars: true})
+	if err != nil {
+		t.Fatalf("Load(config.sample.json): %v", err)
+	}
+	if cfg.App.Name != "aria" || len(cfg.Storage) == 0 || len(cfg.Adapters) == 0 {
+		t.Fatalf("sample loaded incompletely: %+v", cfg.App)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Secrets Sample: config.secrets.sample.json loads once every store it names exists
+//||
+//|| file:// paths under /run/secrets are redirected to a temp dir; the other schemes use their
+//|| real providers (sealed file + master key, a fake Vault, environment variables).
+//||------------------------------------------------------------------------------------------------||
+
+func TestLoad_SecretsSampleConfig(t *testing.T) {
+	tmp := t.TempDir()
+	writeJSON(t, tmp, "db_main_password", "db-pass\n")
+	RegisterSecretProvider("file", SecretProviderFunc(func(ref SecretRef) (string, error) {
+		ref.Path = filepath.Join(tmp, strings.TrimPrefix(ref.Path, "/run/secrets/"))
+		return resolveFile(ref)
+	}))
+	t.Cleanup(func() { RegisterSecretProvider("file", SecretProviderFunc(resolveFile)) })
+
+	sealedPath := filepath.Join(tmp, "secrets.sealed")
+	sealed := map[string]string{"s3_access_key": "AKIA", "s3_secret_key": "s3-secret", "jwt_secret": "jwt"}
+	if err := SealSecrets(sealedPath, []byte("master"), sealed); err != nil {
+		t.Fatalf("SealSecrets: %v", err)
+	}
+	t.Setenv(EnvSecretsFile, sealedPath)
+	t.Setenv(EnvMasterKey, "master")
+
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"data":{"verify_db_pass":"vault-pass"}}}`)
+	}))
+	defer vault.Close()
+	t.Setenv("VAULT_ADDR", vault.URL)
+	t.Setenv("VAULT_TOKEN", "tok")
+
+	t.Setenv("RABBITMQ_PASSWORD", "mq-pass")
+	t.Setenv("STRIPE_API_KEY", "sk_test")
+	t.Setenv("STRIPE_WEBHOOK_SECRET", "whsec")
+
+	cfg, err := Load(LoadOptions{Path: filepath.Join("..", "config.secrets.sample.json"), NoEnvVars: true})
+	if err != nil {
+		t.Fatalf("Load(config.secrets.sample.json): %v", err)
+	}
+	got := []string{cfg.DB["main"].Password, cfg.DB["verify"].Password, cfg.Storage["assets"].AccessKey,
+		cfg.Storage["assets"].SecretKey, cfg.Queue["main"].Password, cfg.Auth.JWTSecret}
+	want := []string{"db-pass", "vault-pass", "AKIA", "s3-secret", "mq-pass", "jwt"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("resolved = %v, want %v", got, want)
+	}
+	if !strings.Contains(string(cfg.Adapters["stripe"]), "whsec") {
+		t.Fatalf("adapter secrets not resolved: %s", cfg.Adapters["stripe"])
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Console: the debug dump of the merged config never shows secrets
+//||------------------------------------------------------------------------------------------------||
+
+func TestPrintConsoleConfig_Redacts(t *testing.T) {
+	c := &Config{
+		App:   AppConfig{Name: "a", Debug: true},
+		DB:    map[string]DBInstanceConfig{"main": {Driver: "postgres", Password: "db-secret"}},
+		Auth:  AuthConfig{JWTSecret: "jwt-secret"},
+		Cache: map[string]CacheInstanceConfig{"c": {Backend: "redis", Password: "cache-secret"}},
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	PrintConsoleConfig(c)
+	os.Stdout = stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+
+	for _, secret := range []string{"db-secret", "jwt-secret", "cache-secret"} {
+		if strings.Contains(string(out), secret) {
+			t.Fatalf("console dump shows %q:\n%s", secret, out)
+		}
+	}
+	if !strings.Contains(string(out), "*******") {
+		t.Fatalf("console dump has no redacted fields:\n%s", out)
+	}
+}
@@ -10,60 +10,285 @@ package config
 //||------------------------------------------------------------------------------------------------||
 
 import (
-	"errors"
 	"fmt"
+	"sort"
 	"strings"
 )
 
 //||------------------------------------------------------------------------------------------------||
-//|| validate: minimal sanity checks
+//|| Problem / ValidationError: every issue found, keyed by JSON path ("storage.assets.bucket")
 //||------------------------------------------------------------------------------------------------||
 
-func validate(c *Config) error {
+type Problem struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (p Problem) String() string {
+	return p.Path + " " + p.Message
+}
 
-	//||------------------------------------------------------------------------------------------------||
-	//|| App
-	//||------------------------------------------------------------------------------------------------||
+type ValidationError struct {
+	Problems []Problem
+}
 
-	if c.App.Name == "" {
-		return errors.New("app.name is required")
+func (e *ValidationError) Error() string {
+	lines := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		lines[i] = p.String()
 	}
-	if c.App.Port <= 0 || c.App.Port > 65535 {
-		return fmt.Errorf("app.port out of range: %d", c.App.Port)
+	if len(lines) == 1 {
+		return lines[0]
 	}
+	return fmt.Sprintf("%d problems:\n  %s", len(lines), strings.Join(lines, "\n  "))
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Validate: check every section; returns *ValidationError listing all problems (sorted by path)
+//||------------------------------------------------------------------------------------------------||
 
-	//||------------------------------------------------------------------------------------------------||
-	//|| HTTP
-	//||------------------------------------------------------------------------------------------------||
+func Validate(c *Config) error {
+	v := &validator{}
 
+	v.app(c.App)
 	for name, h := range c.HTTP {
-		if h.Port <= 0 || h.Port > 65535 {
-			return fmt.Errorf("http[%s].port out of range: %d", name, h.Port)
+		v.http("http."+name, h)
+	}
+	for name, db := range c.DB {
+		v.db("db."+name, db)
+	}
+	for name, cache := range c.Cache {
+		v.cache("cache."+name, cache, c.Cache)
+	}
+	for name, st := range c.Storage {
+		v.storage("storage."+name, st)
+	}
+	for name, q := range c.Queue {
+		v.queue("queue."+name, q)
+	}
+	v.locale(c.Locale)
+	for name, raw := range c.Adapters {
+		v.adapter(name, raw)
+	}
+
+	if len(v.problems) == 0 {
+		return nil
+	}
+	sort.SliceStable(v.problems, func(i, j int) bool { return v.problems[i].Path < v.problems[j].Path })
+	return &ValidationError{Problems: v.problems}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| validator
+//||------------------------------------------------------------------------------------------------||
+
+type validator struct {
+	problems []Problem
+}
+
+func (v *validator) add(path, format string, args ...any) {
+	v.problems = append(v.problems, Problem{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) required(path, value, why string) {
+	if strings.TrimSpace(value) == "" {
+		v.add(path, "required%s", why)
+	}
+}
+
+func (v *validator) port(path string, port int, required bool) {
+	if port == 0 && !required {
+		return
+	}
+	if port <= 0 || port > 65535 {
+		v.add(path, "out of range: %d", port)
+	}
+}
+
+func (v *validator) nonNegative(path string, n int64) {
+	if n < 0 {
+		v.add(path, "must not be negative: %d", n)
+	}
+}
+
+// backend checks name against the registry for section; it reports and returns false when unusable.
+func (v *validator) backend(section, path, name string) bool {
+	if name == "" {
+		v.add(path, "required")
+		return false
+	}
+	if known, checked := backendKnown(section, name); checked && !known {
+		v.add(path, "unsupported: %q (registered: %s)", name, strings.Join(Backends(section), ", "))
+		return false
+	}
+	return true
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| App
+//||------------------------------------------------------------------------------------------------||
+
+func (v *validator) app(a AppConfig) {
+	v.required("app.name", a.Name, "")
+	v.port("app.port", a.Port, true)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| HTTP
+//||------------------------------------------------------------------------------------------------||
+
+func (v *validator) http(path string, h HTTPInstanceConfig) {
+	v.port(path+".port", h.Port, true)
+	switch strings.ToLower(h.Backend) {
+	case "mux", "http":
+		// ok
+	default:
+		v.add(path+".backend", "unsupported: %q (use 'mux' or 'http')", h.Backend)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Database
+//||------------------------------------------------------------------------------------------------||
+
+func (v *validator) db(path string, db DBInstanceConfig) {
+	if !v.backend(SectionDB, path+".driver", db.Driver) {
+		return
+	}
+	driver := strings.ToLower(db.Driver)
+	needed := " for " + driver
+	switch driver {
+	case "postgres", "mysql", "mariadb":
+		v.required(path+".host", db.Host, needed)
+		v.required(path+".database", db.Database, needed)
+	case "mongo":
+		v.required(path+".host", db.Host, needed+" (connection URI)")
+		v.required(path+".database", db.Database, needed)
+	}
+	v.port(path+".port", db.Port, false)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Cache
+//||------------------------------------------------------------------------------------------------||
+
+func (v *validator) cache(path string, c CacheInstanceConfig, all map[string]CacheInstanceConfig) {
+	if !v.backend(SectionCache, path+".backend", c.Backend) {
+		return
+	}
+	backend := strings.ToLower(c.Backend)
+	needed := " for " + backend
+	switch backend {
+	case "redis", "keydb":
+		switch strings.ToLower(c.Mode) {
+		case "", "single":
+			v.required(path+".host", c.Host, needed)
+		case "sentinel":
+			v.required(path+".master_name", c.MasterName, " for sentinel mode")
+			if len(c.SentinelAddrs) == 0 {
+				v.add(path+".sentinel_addrs", "required for sentinel mode")
+			}
+		case "cluster":
+			if len(c.Servers) == 0 {
+				v.add(path+".servers", "required for cluster mode")
+			}
+		default:
+			v.add(path+".mode", "unsupported: %q (use single, sentinel or cluster)", c.Mode)
+		}
+		if c.TLSCertFile != "" && c.TLSKeyFile == "" {
+			v.add(path+".tls_key", "required with tls_cert")
+		}
+	case "memcached":
+		if len(c.Servers) == 0 && c.Host == "" {
+			v.add(path+".servers", "required%s (or host)", needed)
 		}
-		switch strings.ToLower(h.Backend) {
-		case "mux", "http":
-			// ok
+	case "memory", "layered":
+		switch strings.ToLower(c.Eviction) {
+		case "", "lru", "lfu":
 		default:
-			return fmt.Errorf("http[%s].backend unsupported: %q (use 'mux' or 'http')", name, h.Backend)
+			v.add(path+".eviction", "unsupported: %q (use lru or lfu)", c.Eviction)
 		}
+		if backend == "layered" {
+			v.layeredL2(path, c, all)
+		}
+	}
+	v.port(path+".port", c.Port, false)
+	v.nonNegative(path+".max_entries", int64(c.MaxEntries))
+	v.nonNegative(path+".max_bytes", c.MaxBytes)
+	v.nonNegative(path+".l1_ttl", int64(c.L1TTL))
+}
+
+func (v *validator) layeredL2(path string, c CacheInstanceConfig, all map[string]CacheInstanceConfig) {
+	if c.L2 == "" {
+		v.add(path+".l2", "required for layered")
+		return
+	}
+	l2, ok := all[c.L2]
+	if !ok {
+		v.add(path+".l2", "references unknown cache %q", c.L2)
+		return
 	}
+	switch strings.ToLower(l2.Backend) {
+	case "redis", "keydb":
+	default:
+		v.add(path+".l2", "cache %q must be redis or keydb, not %q", c.L2, l2.Backend)
+	}
+}
 
-	//||------------------------------------------------------------------------------------------------||
-	//|| Database
-	//||------------------------------------------------------------------------------------------------||
+//||------------------------------------------------------------------------------------------------||
+//|| Storage
+//||------------------------------------------------------------------------------------------------||
 
-	for name, db := range c.DB {
-		switch strings.ToLower(db.Driver) {
-		case "postgres", "mysql", "mariadb", "mongo":
-			// ok
-		default:
-			return fmt.Errorf("db[%s].driver unsupported: %q", name, db.Driver)
+func (v *validator) storage(path string, s StorageInstanceConfig) {
+	if !v.backend(SectionStorage, path+".backend", s.Backend) {
+		return
+	}
+	backend := strings.ToLower(s.Backend)
+	needed := " for " + backend
+	switch backend {
+	case "s3":
+		v.required(path+".bucket", s.Bucket, needed)
+		v.required(path+".region", s.Region, needed)
+	case "minio":
+		v.required(path+".bucket", s.Bucket, needed)
+		v.required(path+".endpoint", s.Endpoint, needed)
+	case "azure", "gcp", "gcs":
+		v.required(path+".bucket", s.Bucket, needed)
+	case "local":
+		v.required(path+".dir", s.Dir, needed)
+		if s.PublicURL != "" {
+			v.required(path+".sign_key", s.SignKey, " with public_url")
 		}
 	}
+	if (s.AccessKey == "") != (s.SecretKey == "") {
+		v.add(path+".secret_key", "access_key and secret_key must be set together")
+	}
+	v.nonNegative(path+".timeout", int64(s.Timeout))
+	v.nonNegative(path+".max_retries", int64(s.MaxRetries))
+	v.nonNegative(path+".retry_backoff", int64(s.RetryBackoff))
+	v.nonNegative(path+".retry_max_backoff", int64(s.RetryMaxBackoff))
+}
 
-	//||------------------------------------------------------------------------------------------------||
-	//|| Done
-	//||------------------------------------------------------------------------------------------------||
+//||------------------------------------------------------------------------------------------------||
+//|| Queue
+//||------------------------------------------------------------------------------------------------||
 
-	return nil
+func (v *validator) queue(path string, q QueueInstanceConfig) {
+	if !v.backend(SectionQueue, path+".backend", q.Backend) {
+		return
+	}
+	if strings.EqualFold(q.Backend, "rabbitmq") {
+		v.required(path+".host", q.Host, " for rabbitmq")
+	}
+	v.port(path+".port", q.Port, false)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Locale
+//||------------------------------------------------------------------------------------------------||
+
+func (v *validator) locale(l LocaleConfig) {
+	if l.Default != "" && len(l.Supported) > 0 && !stringInSlice(l.Default, l.Supported) {
+		v.add("locale.default", "%q is not in locale.supported", l.Default)
+	}
 }
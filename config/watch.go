@@ -0,0 +1,241 @@
+//||------------------------------------------------------------------------------------------------||
+//|| Config Package: Hot Reload & Change Subscriptions
+//|| watch.go
+//||------------------------------------------------------------------------------------------------||
+
+package config
+
+//||------------------------------------------------------------------------------------------------||
+//|| Import
+//||------------------------------------------------------------------------------------------------||
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Source: one loaded config with the options to reload it and its change subscribers
+//||
+//|| The package singleton (Init, Reload, Watch, OnChange) is one Source; every app.Application
+//|| built by app.New owns another, so several applications reload independently.
+//||------------------------------------------------------------------------------------------------||
+
+// ChangeFunc receives the previous and the new config; both are read-only snapshots.
+type ChangeFunc func(old, new *Config)
+
+type subscriber struct {
+	id      int
+	section string
+	fn      ChangeFunc
+}
+
+type Source struct {
+	mu       sync.RWMutex
+	cfg      *Config
+	opts     LoadOptions
+	reloadMu sync.Mutex // one Reload at a time, so subscribers see swaps in order
+	subsMu   sync.Mutex
+	subs     []subscriber
+	subsNext int
+}
+
+const reloadDebounce = 100 * time.Millisecond
+
+// NewSource wraps cfg, already loaded from opts, so it can be reloaded and watched.
+func NewSource(opts LoadOptions, cfg *Config) *Source {
+	return &Source{cfg: cfg, opts: opts}
+}
+
+// Config is the current snapshot (nil before the first load).
+func (s *Source) Config() *Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cfg
+}
+
+func (s *Source) set(opts LoadOptions, cfg *Config) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cfg, s.opts = cfg, opts
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| OnChange: call fn after a reload changes section ("app", "db", "http", ...; "*" = any)
+//||------------------------------------------------------------------------------------------------||
+
+func (s *Source) OnChange(section string, fn ChangeFunc) (cancel func()) {
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	s.subsNext++
+	id := s.subsNext
+	s.subs = append(s.subs, subscriber{id: id, section: strings.ToLower(section), fn: fn})
+	return func() {
+		s.subsMu.Lock()
+		defer s.subsMu.Unlock()
+		for i, sub := range s.subs {
+			if sub.id == id {
+				s.subs = append(s.subs[:i], s.subs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Reload: re-read every layer, validate, swap, then notify (the old config stays on any error)
+//||------------------------------------------------------------------------------------------------||
+
+func (s *Source) Reload() error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	s.mu.RLock()
+	old, opts := s.cfg, s.opts
+	s.mu.RUnlock()
+	if old == nil {
+		return fmt.Errorf("config not loaded")
+	}
+
+	next, err := Load(opts)
+	if err != nil {
+		return err
+	}
+	changed := changedSections(old, next)
+	if len(changed) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	s.cfg = next
+	s.mu.Unlock()
+
+	s.notify(changed, old, next)
+	return nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Watch: reload when the base file or its env overlay changes, until ctx is done
+//||
+//|| The directory is watched (not the file) so editors that save via rename are seen.
+//||------------------------------------------------------------------------------------------------||
+
+func (s *Source) Watch(ctx context.Context) error {
+	s.mu.RLock()
+	path, loaded := s.opts.Path, s.cfg != nil
+	s.mu.RUnlock()
+	if !loaded {
+		return fmt.Errorf("config not loaded")
+	}
+	if path == "" {
+		return fmt.Errorf("config was not loaded from a file, nothing to watch")
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create watcher: %w", err)
+	}
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return fmt.Errorf("watch %s: %w", filepath.Dir(path), err)
+	}
+
+	stem := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	relevant := func(name string) bool {
+		base := filepath.Base(name)
+		return base == filepath.Base(path) || strings.HasPrefix(base, stem+".")
+	}
+
+	go func() {
+		defer watcher.Close()
+		var debounce <-chan time.Time // editors write in bursts; reload once they settle
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 && relevant(event.Name) {
+					debounce = time.After(reloadDebounce)
+				}
+			case <-debounce:
+				debounce = nil
+				log.Printf("[config] change detected, reloading...")
+				if err := s.Reload(); err != nil {
+					log.Printf("[config] reload failed, keeping current config: %v", err)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("[config] watcher error: %v", err)
+			}
+		}
+	}()
+	return nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Singleton: the Source behind Init / app.Init (apps from app.New use their own Reload/Watch)
+//||------------------------------------------------------------------------------------------------||
+
+var defaultSource = &Source{}
+
+// DefaultSource is the Source that Init loads into.
+func DefaultSource() *Source {
+	return defaultSource
+}
+
+func OnChange(section string, fn ChangeFunc) (cancel func()) {
+	return defaultSource.OnChange(section, fn)
+}
+
+func Reload() error {
+	return defaultSource.Reload()
+}
+
+func Watch(ctx context.Context) error {
+	return defaultSource.Watch(ctx)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| changedSections: json names of the top-level sections that differ
+//||------------------------------------------------------------------------------------------------||
+
+func changedSections(old, next *Config) []string {
+	var changed []string
+	ov, nv := reflect.ValueOf(*old), reflect.ValueOf(*next)
+	t := ov.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if !reflect.DeepEqual(ov.Field(i).Interface(), nv.Field(i).Interface()) {
+			changed = append(changed, jsonName(t.Field(i)))
+		}
+	}
+	return changed
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| notify: each subscriber runs once per reload, in registration order
+//||------------------------------------------------------------------------------------------------||
+
+func (s *Source) notify(changed []string, old, next *Config) {
+	s.subsMu.Lock()
+	list := append([]subscriber(nil), s.subs...)
+	s.subsMu.Unlock()
+
+	for _, sub := range list {
+		if sub.section == "*" || stringInSlice(sub.section, changed) {
+			sub.fn(old, next)
+		}
+	}
+}
@@ -8,6 +8,7 @@ package db
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/ralphferrara/aria/config"
@@ -21,7 +22,7 @@ import (
 //||------------------------------------------------------------------------------------------------||
 
 func buildDSN(cfg config.DBInstanceConfig) string {
-	switch cfg.Driver {
+	switch strings.ToLower(cfg.Driver) {
 	case "postgres":
 		return fmt.Sprintf(
 			"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
@@ -48,8 +49,10 @@ func connectMongo(cfg config.DBInstanceConfig) (*mongo.Database, error) {
 	if err != nil {
 		return nil, err
 	}
-	if err := client.Ping(ctx, nil); err != nil {
-		return nil, err
+	if !cfg.Lazy {
+		if err := client.Ping(ctx, nil); err != nil {
+			return nil, err
+		}
 	}
 	return client.Database(cfg.Database), nil
 }
@@ -13,67 +13,58 @@ import (
 	"fmt"
 
 	"github.com/ralphferrara/aria/config"
-
-	_ "github.com/go-sql-driver/mysql"
-	_ "github.com/jackc/pgx/v5/stdlib"
-	"gorm.io/driver/mysql"
-	"gorm.io/driver/postgres"
-	"gorm.io/gorm"
 )
 
 //||------------------------------------------------------------------------------------------------||
 //|| DB: Init - Connects all DBs from config
 //||------------------------------------------------------------------------------------------------||
 
-func Init(cfg *config.Config) (map[string]*GormWrapper, map[string]*MongoWrapper, error) {
+func Init(cfg *config.Config) (map[string]Database, error) {
 
-	sqlDB := make(map[string]*GormWrapper)
-	mongoDB := make(map[string]*MongoWrapper)
+	dbs := make(map[string]Database)
 
 	for name, dbCfg := range cfg.DB {
-		switch dbCfg.Driver {
+		factory, ok := lookup(dbCfg.Driver)
+		if !ok {
+			closeAll(dbs)
+			return nil, &config.InstanceError{Section: config.SectionDB, Name: name, Err: fmt.Errorf("unsupported db driver: %s", dbCfg.Driver)}
+		}
+		d, err := factory(name, dbCfg)
+		if err != nil {
+			closeAll(dbs)
+			return nil, &config.InstanceError{Section: config.SectionDB, Name: name, Err: fmt.Errorf("%s connect failed: %w", dbCfg.Driver, err)}
+		}
+		dbs[name] = d
+		fmt.Printf("\n[ DB ] - Initialized database: %s (backend: %s)", name, dbCfg.Driver)
+	}
 
-		//||------------------------------------------------------------------------------------------------||
-		//|| PostGres
-		//||------------------------------------------------------------------------------------------------||
-		case "postgres":
-			dsn := buildDSN(dbCfg)
-			db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
-			if err != nil {
-				return nil, nil, fmt.Errorf("failed to connect to postgres '%s': %w", name, err)
-			}
-			sqlDB[name] = &GormWrapper{Name: name, DB: db}
-			fmt.Printf("\n[ DB ] - Initialized database: %s (backend: %s)", name, dbCfg.Driver)
+	return dbs, nil
+}
 
-		//||------------------------------------------------------------------------------------------------||
-		//|| MySQL, MariaDB
-		//||------------------------------------------------------------------------------------------------||
-		case "mysql", "mariadb":
-			dsn := buildDSN(dbCfg)
-			db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
-			if err != nil {
-				return nil, nil, fmt.Errorf("failed to connect to mysql/mariadb '%s': %w", name, err)
-			}
-			sqlDB[name] = &GormWrapper{Name: name, DB: db}
-			fmt.Printf("\n[ DB ] - Initialized database: %s (backend: %s)", name, dbCfg.Driver)
-		//||------------------------------------------------------------------------------------------------||
-		//|| MongoDB
-		//||------------------------------------------------------------------------------------------------||
-		case "mongo":
-			mdb, err := connectMongo(dbCfg)
-			if err != nil {
-				return nil, nil, fmt.Errorf("mongo connect failed for '%s': %w", name, err)
-			}
-			mongoDB[name] = &MongoWrapper{Name: name, Database: mdb}
-			fmt.Printf("\n[ DB ] - Initialized database: %s (backend: %s)", name, dbCfg.Driver)
+//||------------------------------------------------------------------------------------------------||
+//|| Split: typed views for the built-in wrappers (other drivers stay in the full map)
+//||------------------------------------------------------------------------------------------------||
 
-		//||------------------------------------------------------------------------------------------------||
-		//|| Default
-		//||------------------------------------------------------------------------------------------------||
-		default:
-			return nil, nil, fmt.Errorf("unsupported db driver: %s", dbCfg.Driver)
+func Split(dbs map[string]Database) (map[string]*GormWrapper, map[string]*MongoWrapper) {
+	sqlDB := make(map[string]*GormWrapper)
+	mongoDB := make(map[string]*MongoWrapper)
+	for name, d := range dbs {
+		switch v := d.(type) {
+		case *GormWrapper:
+			sqlDB[name] = v
+		case *MongoWrapper:
+			mongoDB[name] = v
 		}
 	}
+	return sqlDB, mongoDB
+}
 
-	return sqlDB, mongoDB, nil
+//||------------------------------------------------------------------------------------------------||
+//|| closeAll: release whatever was opened before a failure
+//||------------------------------------------------------------------------------------------------||
+
+func closeAll(dbs map[string]Database) {
+	for _, d := range dbs {
+		_ = d.Close()
+	}
 }
@@ -0,0 +1,129 @@
+//||------------------------------------------------------------------------------------------------||
+//|| DB Package: Driver Registry
+//|| registry.go
+//||------------------------------------------------------------------------------------------------||
+
+package db
+
+//||------------------------------------------------------------------------------------------------||
+//|| Import
+//||------------------------------------------------------------------------------------------------||
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ralphferrara/aria/config"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Factory: opens one named database from its config block
+//||------------------------------------------------------------------------------------------------||
+
+type Factory func(name string, cfg config.DBInstanceConfig) (Database, error)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Registry
+//||------------------------------------------------------------------------------------------------||
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{}
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Register: add (or replace) a driver; names are case-insensitive
+//||------------------------------------------------------------------------------------------------||
+
+func Register(driver string, factory Factory) {
+	if driver == "" || factory == nil {
+		panic("db: Register requires a driver name and factory")
+	}
+	registryMu.Lock()
+	registry[strings.ToLower(driver)] = factory
+	registryMu.Unlock()
+	config.RegisterBackend(config.SectionDB, driver)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Drivers: registered driver names (sorted)
+//||------------------------------------------------------------------------------------------------||
+
+func Drivers() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	out := make([]string, 0, len(registry))
+	for name := range registry {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| lookup
+//||------------------------------------------------------------------------------------------------||
+
+func lookup(driver string) (Factory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	f, ok := registry[strings.ToLower(driver)]
+	return f, ok
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| GormFactory: adapts a gorm dialector builder (e.g. sqlite.Open(cfg.Database)) into a Factory
+//||------------------------------------------------------------------------------------------------||
+
+func GormFactory(dialector func(cfg config.DBInstanceConfig) gorm.Dialector) Factory {
+	return func(name string, cfg config.DBInstanceConfig) (Database, error) {
+		db, err := gorm.Open(dialector(cfg), &gorm.Config{DisableAutomaticPing: cfg.Lazy})
+		if err != nil {
+			return nil, err
+		}
+		return &GormWrapper{Name: name, DB: db}, nil
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Built-in Drivers
+//||------------------------------------------------------------------------------------------------||
+
+func init() {
+	Register("postgres", GormFactory(postgresDialector))
+	Register("mysql", GormFactory(mysqlDialector))
+	Register("mariadb", GormFactory(mysqlDialector))
+	Register("mongo", newMongo)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Dialectors (lazy: no version probe, so nothing dials until the first query)
+//||------------------------------------------------------------------------------------------------||
+
+func postgresDialector(cfg config.DBInstanceConfig) gorm.Dialector {
+	return postgres.Open(buildDSN(cfg))
+}
+
+func mysqlDialector(cfg config.DBInstanceConfig) gorm.Dialector {
+	return mysql.New(mysql.Config{DSN: buildDSN(cfg), SkipInitializeWithVersion: cfg.Lazy})
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| MongoDB
+//||------------------------------------------------------------------------------------------------||
+
+func newMongo(name string, cfg config.DBInstanceConfig) (Database, error) {
+	mdb, err := connectMongo(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("mongo connect failed: %w", err)
+	}
+	return &MongoWrapper{Name: name, Database: mdb}, nil
+}
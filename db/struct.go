@@ -10,10 +10,22 @@ package db
 //||------------------------------------------------------------------------------------------------||
 
 import (
+	"context"
+	"fmt"
+
 	"go.mongodb.org/mongo-driver/mongo"
 	"gorm.io/gorm"
 )
 
+//||------------------------------------------------------------------------------------------------||
+//|| Database: Common Interface (implemented by every registered driver)
+//||------------------------------------------------------------------------------------------------||
+
+type Database interface {
+	Ping(ctx context.Context) error
+	Close() error
+}
+
 //||------------------------------------------------------------------------------------------------||
 //|| Gorm Wrapper
 //||------------------------------------------------------------------------------------------------||
@@ -23,6 +35,36 @@ type GormWrapper struct {
 	DB   *gorm.DB
 }
 
+//||------------------------------------------------------------------------------------------------||
+//|| Gorm: Ping (underlying sql.DB pool)
+//||------------------------------------------------------------------------------------------------||
+
+func (g *GormWrapper) Ping(ctx context.Context) error {
+	if g == nil || g.DB == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	sqlDB, err := g.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Gorm: Close (underlying sql.DB pool)
+//||------------------------------------------------------------------------------------------------||
+
+func (g *GormWrapper) Close() error {
+	if g == nil || g.DB == nil {
+		return nil
+	}
+	sqlDB, err := g.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
 //||------------------------------------------------------------------------------------------------||
 //|| Mongo Wrapper
 //||------------------------------------------------------------------------------------------------||
@@ -32,6 +74,28 @@ type MongoWrapper struct {
 	Database *mongo.Database
 }
 
+//||------------------------------------------------------------------------------------------------||
+//|| Mongo: Ping (primary)
+//||------------------------------------------------------------------------------------------------||
+
+func (m *MongoWrapper) Ping(ctx context.Context) error {
+	if m == nil || m.Database == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	return m.Database.Client().Ping(ctx, nil)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Mongo: Close (disconnects the shared client)
+//||------------------------------------------------------------------------------------------------||
+
+func (m *MongoWrapper) Close() error {
+	if m == nil || m.Database == nil {
+		return nil
+	}
+	return m.Database.Client().Disconnect(context.Background())
+}
+
 //||------------------------------------------------------------------------------------------------||
 //|| Globals
 //||------------------------------------------------------------------------------------------------||
@@ -40,3 +104,12 @@ var (
 	SQL   = map[string]*GormWrapper{}
 	Mongo = map[string]*MongoWrapper{}
 )
+
+//||------------------------------------------------------------------------------------------------||
+//|| Compile-time interface checks
+//||------------------------------------------------------------------------------------------------||
+
+var (
+	_ Database = (*GormWrapper)(nil)
+	_ Database = (*MongoWrapper)(nil)
+)
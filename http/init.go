@@ -11,9 +11,10 @@ package http
 
 import (
 	"fmt"
+	"net"
 	"net/http"
-	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -175,6 +176,11 @@ func (h *HTTPWrapper) Start() error {
 	if h.Server == nil {
 		return fmt.Errorf("HTTP server [%s] is not initialized", h.Name)
 	}
+	h.startOnce.Do(func() { h.startErr = h.start() })
+	return h.startErr
+}
+
+func (h *HTTPWrapper) start() error {
 
 	// Rebuild handler chain using latest config values
 	handler := h.RouterOrMux()
@@ -190,12 +196,20 @@ func (h *HTTPWrapper) Start() error {
 
 	h.Server.Handler = handler // ← apply rebuilt chain
 
+	//||------------------------------------------------------------------------------------------------||
+	//|| Bind first so a taken port is returned to the caller, then serve in the background
+	//||------------------------------------------------------------------------------------------------||
+
+	ln, err := net.Listen("tcp", h.Server.Addr)
+	if err != nil {
+		return fmt.Errorf("HTTP server [%s] listen failed: %w", h.Name, err)
+	}
+
 	fmt.Printf("\n[HTTP] - Starting HTTP server [%s] on port %s (backend: %s)\n", h.Name, h.HTTPConfig.Port, h.HTTPConfig.Backend)
 
 	go func() {
-		if err := h.Server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := h.Server.Serve(ln); err != nil && err != http.ErrServerClosed {
 			fmt.Printf("[HTTP] - Server [%s] failed: %v\n", h.Name, err)
-			os.Exit(1)
 		}
 	}()
 
@@ -215,3 +229,34 @@ func (h *HTTPWrapper) RouterOrMux() http.Handler {
 	}
 	return http.DefaultServeMux
 }
+
+//||------------------------------------------------------------------------------------------------||
+//|| Handle: register an exact-path handler on whichever router this wrapper uses
+//||------------------------------------------------------------------------------------------------||
+
+func (h *HTTPWrapper) Handle(path string, handler http.Handler) {
+	switch {
+	case h.Router != nil:
+		h.Router.Handle(path, handler)
+	case h.ServeMux != nil:
+		h.ServeMux.Handle(path, handler)
+	default:
+		http.DefaultServeMux.Handle(path, handler)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| HandlePrefix: register a handler for every path under prefix ("/files" serves "/files/...")
+//||------------------------------------------------------------------------------------------------||
+
+func (h *HTTPWrapper) HandlePrefix(prefix string, handler http.Handler) {
+	prefix = strings.TrimRight(prefix, "/") + "/"
+	switch {
+	case h.Router != nil:
+		h.Router.PathPrefix(prefix).Handler(handler)
+	case h.ServeMux != nil:
+		h.ServeMux.Handle(prefix, handler)
+	default:
+		http.DefaultServeMux.Handle(prefix, handler)
+	}
+}
@@ -11,6 +11,7 @@ package http
 
 import (
 	"net/http"
+	"sync"
 
 	"github.com/gorilla/mux"
 )
@@ -37,6 +38,8 @@ type HTTPWrapper struct {
 	Handler    http.Handler
 	Router     *mux.Router
 	ServeMux   *http.ServeMux
+	startOnce  sync.Once
+	startErr   error
 }
 
 //||------------------------------------------------------------------------------------------------||
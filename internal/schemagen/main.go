@@ -0,0 +1,46 @@
+//||------------------------------------------------------------------------------------------------||
+//|| Schemagen: writes the config JSON Schema for editors and CI
+//|| main.go
+//||
+//|| Run through go generate (see config/schema.go):
+//||   go generate ./config
+//||------------------------------------------------------------------------------------------------||
+
+package main
+
+//||------------------------------------------------------------------------------------------------||
+//|| Import
+//||------------------------------------------------------------------------------------------------||
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	_ "github.com/ralphferrara/aria/app" // links every subsystem, so backend enums are complete
+	"github.com/ralphferrara/aria/config"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Main: -o <file> writes the schema there, otherwise to stdout
+//||------------------------------------------------------------------------------------------------||
+
+func main() {
+	out := flag.String("o", "", "output file (default stdout)")
+	flag.Parse()
+
+	data, err := config.JSONSchemaJSON()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "schemagen:", err)
+		os.Exit(1)
+	}
+	data = append(data, '\n')
+	if *out == "" {
+		_, _ = os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(*out, data, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "schemagen:", err)
+		os.Exit(1)
+	}
+}
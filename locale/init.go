@@ -1,5 +1,7 @@
 package locale
 
+import "fmt"
+
 //||------------------------------------------------------------------------------------------------||
 //|| Globals
 //||------------------------------------------------------------------------------------------------||
@@ -13,11 +15,11 @@ var (
 //|| Init: Creates LocaleWrapper and loads translations
 //||------------------------------------------------------------------------------------------------||
 
-func Init(dir string) LocaleWrapper {
+func Init(dir string) (LocaleWrapper, error) {
 	Directory = dir
 
 	if err := LoadRendered(dir); err != nil {
-		panic("failed to load translations: " + err.Error())
+		return LocaleWrapper{}, fmt.Errorf("failed to load translations: %w", err)
 	}
 
 	return LocaleWrapper{
@@ -26,5 +28,5 @@ func Init(dir string) LocaleWrapper {
 			return GetTranslation(section, term, lang)
 		},
 		Load: LoadRendered,
-	}
+	}, nil
 }
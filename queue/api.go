@@ -6,6 +6,8 @@
 package queue
 
 import (
+	"context"
+	"fmt"
 	"time"
 
 	"github.com/streadway/amqp"
@@ -66,3 +68,39 @@ func (q *RabbitMQWrapper) Consume(queue string) (<-chan amqp.Delivery, error) {
 		nil,   // args
 	)
 }
+
+//||------------------------------------------------------------------------------------------------||
+//|| Ping (RabbitMQ): connection and channel are still open
+//||------------------------------------------------------------------------------------------------||
+
+func (q *RabbitMQWrapper) Ping() error {
+	if q == nil || q.Conn == nil || q.Channel == nil {
+		return fmt.Errorf("queue not initialized")
+	}
+	if q.Conn.IsClosed() {
+		return fmt.Errorf("queue '%s' connection closed", q.Name)
+	}
+	return nil
+}
+
+// PingContext is Ping; it never blocks, so ctx only matters if it is already done.
+func (q *RabbitMQWrapper) PingContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return q.Ping()
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Close (RabbitMQ)
+//||------------------------------------------------------------------------------------------------||
+
+func (q *RabbitMQWrapper) Close() error {
+	if q == nil || q.Conn == nil {
+		return nil
+	}
+	if q.Channel != nil {
+		_ = q.Channel.Close()
+	}
+	return q.Conn.Close()
+}
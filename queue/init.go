@@ -19,41 +19,31 @@ import (
 //|| Init (build all queues from main config)
 //||------------------------------------------------------------------------------------------------||
 
-func Init(cfg *config.Config) (map[string]*RabbitMQWrapper, error) {
+func Init(cfg *config.Config) (map[string]Queue, error) {
 
 	//||------------------------------------------------------------------------------------------------||
 	//|| Output Map
 	//||------------------------------------------------------------------------------------------------||
 
-	queues := make(map[string]*RabbitMQWrapper)
+	queues := make(map[string]Queue)
 
 	//||------------------------------------------------------------------------------------------------||
 	//|| Loop Configured Queues
 	//||------------------------------------------------------------------------------------------------||
 
 	for name, qCfg := range cfg.Queue {
-		switch qCfg.Backend {
-
-		//||------------------------------------------------------------------------------------------------||
-		//|| RabbitMQ
-		//||------------------------------------------------------------------------------------------------||
-		case "rabbitmq", "RABBITMQ":
-			conn, ch, err := connectRabbit(qCfg)
-			if err != nil {
-				return nil, fmt.Errorf("queue '%s' rabbitmq connect failed: %w", name, err)
-			}
-			queues[name] = &RabbitMQWrapper{
-				Name:    name,
-				Conn:    conn,
-				Channel: ch,
-			}
-			fmt.Printf("\n[RBBT] - Initializing queue: %s (backend: %s)", name, "RabbitMQ")
-		//||------------------------------------------------------------------------------------------------||
-		//|| Unsupported
-		//||------------------------------------------------------------------------------------------------||
-		default:
-			return nil, fmt.Errorf("unsupported queue backend: %s", qCfg.Backend)
+		factory, ok := lookup(qCfg.Backend)
+		if !ok {
+			closeAll(queues)
+			return nil, &config.InstanceError{Section: config.SectionQueue, Name: name, Err: fmt.Errorf("unsupported queue backend: %s", qCfg.Backend)}
+		}
+		q, err := factory(name, qCfg)
+		if err != nil {
+			closeAll(queues)
+			return nil, &config.InstanceError{Section: config.SectionQueue, Name: name, Err: fmt.Errorf("%s: %w", qCfg.Backend, err)}
 		}
+		queues[name] = q
+		fmt.Printf("\n[QUEU] - Initializing queue: %s (backend: %s)", name, qCfg.Backend)
 	}
 
 	//||------------------------------------------------------------------------------------------------||
@@ -62,3 +52,13 @@ func Init(cfg *config.Config) (map[string]*RabbitMQWrapper, error) {
 
 	return queues, nil
 }
+
+//||------------------------------------------------------------------------------------------------||
+//|| closeAll: release whatever was built before a failure
+//||------------------------------------------------------------------------------------------------||
+
+func closeAll(queues map[string]Queue) {
+	for _, q := range queues {
+		_ = q.Close()
+	}
+}
@@ -0,0 +1,305 @@
+//||------------------------------------------------------------------------------------------------||
+//|| Queue Package: Lazy RabbitMQ (connect on first use, reconnect with backoff)
+//|| lazy.go
+//||------------------------------------------------------------------------------------------------||
+
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ralphferrara/aria/config"
+	"github.com/streadway/amqp"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Lazy: Backoff Bounds
+//||------------------------------------------------------------------------------------------------||
+
+const (
+	lazyMinBackoff = 500 * time.Millisecond
+	lazyMaxBackoff = 30 * time.Second
+)
+
+var ErrQueueClosed = errors.New("queue closed")
+
+//||------------------------------------------------------------------------------------------------||
+//|| LazyRabbitMQ: holds at most one live connection; consumers are re-attached after reconnect
+//||------------------------------------------------------------------------------------------------||
+
+type LazyRabbitMQ struct {
+	Name      string
+	cfg       config.QueueInstanceConfig
+	dial      func() (lazyConn, error) // dialRabbit outside tests
+	mu        sync.Mutex
+	current   lazyConn
+	lastErr   error
+	nextTry   time.Time
+	backoff   time.Duration
+	consumers []lazyConsumer
+	retrying  bool
+	closed    bool
+	dialing   chan struct{} // non-nil while a dial is in flight; closed when it finishes
+	closing   chan struct{} // closed by Close, releases callers waiting on a dial
+}
+
+type lazyConsumer struct {
+	queue   string
+	handler func([]byte)
+}
+
+// lazyConn is a live connection plus the channel that reports when it drops.
+type lazyConn interface {
+	Queue
+	notifyClose() <-chan *amqp.Error
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| NewLazyRabbitMQ: no network I/O until the first Publish/ConsumeQueue
+//||------------------------------------------------------------------------------------------------||
+
+func NewLazyRabbitMQ(name string, cfg config.QueueInstanceConfig) *LazyRabbitMQ {
+	l := &LazyRabbitMQ{Name: name, cfg: cfg, closing: make(chan struct{})}
+	l.dial = func() (lazyConn, error) { return dialRabbit(name, cfg) }
+	return l
+}
+
+func dialRabbit(name string, cfg config.QueueInstanceConfig) (lazyConn, error) {
+	conn, ch, err := connectRabbit(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &RabbitMQWrapper{Name: name, Conn: conn, Channel: ch}, nil
+}
+
+func (q *RabbitMQWrapper) notifyClose() <-chan *amqp.Error {
+	return q.Conn.NotifyClose(make(chan *amqp.Error, 1))
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Lazy: Connection (fails fast with the last error while backing off)
+//||
+//|| The dial runs outside mu, so Close and callers that fail fast never wait on a broker that
+//|| is unreachable; concurrent callers share the one in-flight dial instead of starting more.
+//||------------------------------------------------------------------------------------------------||
+
+func (l *LazyRabbitMQ) conn() (lazyConn, error) {
+	l.mu.Lock()
+	for l.dialing != nil && !l.closed {
+		dialing := l.dialing
+		l.mu.Unlock()
+		select {
+		case <-dialing:
+		case <-l.closing:
+		}
+		l.mu.Lock()
+	}
+	if l.closed {
+		l.mu.Unlock()
+		return nil, ErrQueueClosed
+	}
+	if l.current != nil {
+		w := l.current
+		l.mu.Unlock()
+		return w, nil
+	}
+	if time.Now().Before(l.nextTry) {
+		err := l.lastErr
+		l.mu.Unlock()
+		return nil, fmt.Errorf("queue '%s' reconnecting: %w", l.Name, err)
+	}
+	dialing := make(chan struct{})
+	l.dialing = dialing
+	l.mu.Unlock()
+
+	w, err := l.dial()
+
+	l.mu.Lock()
+	l.dialing = nil
+	close(dialing)
+	if err != nil {
+		l.lastErr = err
+		l.backoff = nextBackoff(l.backoff)
+		l.nextTry = time.Now().Add(l.backoff)
+		l.mu.Unlock()
+		return nil, fmt.Errorf("queue '%s' connect failed: %w", l.Name, err)
+	}
+	if l.closed {
+		l.mu.Unlock()
+		_ = w.Close()
+		return nil, ErrQueueClosed
+	}
+	l.backoff, l.lastErr, l.nextTry = 0, nil, time.Time{}
+	l.current = w
+	consumers := append([]lazyConsumer(nil), l.consumers...)
+	l.mu.Unlock()
+
+	for _, c := range consumers {
+		if err := w.ConsumeQueue(c.queue, c.handler); err != nil {
+			l.mu.Lock()
+			l.lastErr = err
+			l.mu.Unlock()
+		}
+	}
+	go l.watch(w, w.notifyClose())
+	return w, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Lazy: Watch (drop the dead connection; reconnect in background when consumers need it)
+//||------------------------------------------------------------------------------------------------||
+
+func (l *LazyRabbitMQ) watch(w lazyConn, closed <-chan *amqp.Error) {
+	<-closed
+	l.mu.Lock()
+	if l.current == w {
+		l.current = nil
+	}
+	resume := len(l.consumers) > 0 && !l.closed
+	l.mu.Unlock()
+	if resume {
+		l.retry()
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Lazy: Retry (single background loop until connected or closed)
+//||------------------------------------------------------------------------------------------------||
+
+func (l *LazyRabbitMQ) retry() {
+	l.mu.Lock()
+	if l.retrying {
+		l.mu.Unlock()
+		return
+	}
+	l.retrying = true
+	l.mu.Unlock()
+
+	go func() {
+		defer func() {
+			l.mu.Lock()
+			l.retrying = false
+			l.mu.Unlock()
+		}()
+		for {
+			_, err := l.conn()
+			if err == nil || errors.Is(err, ErrQueueClosed) {
+				return
+			}
+			l.mu.Lock()
+			wait := time.Until(l.nextTry)
+			l.mu.Unlock()
+			if wait < lazyMinBackoff {
+				wait = lazyMinBackoff
+			}
+			time.Sleep(wait)
+		}
+	}()
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Lazy: Publish
+//||------------------------------------------------------------------------------------------------||
+
+func (l *LazyRabbitMQ) Publish(queue string, body []byte) error {
+	w, err := l.conn()
+	if err != nil {
+		return err
+	}
+	return w.Publish(queue, body)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Lazy: ConsumeQueue (registration survives reconnects; starts once a connection exists)
+//||
+//|| A failed connect is returned, but the consumer stays registered and is attached by the
+//|| background retry once the broker is reachable.
+//||------------------------------------------------------------------------------------------------||
+
+func (l *LazyRabbitMQ) ConsumeQueue(queue string, handler func([]byte)) error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return ErrQueueClosed
+	}
+	l.consumers = append(l.consumers, lazyConsumer{queue: queue, handler: handler})
+	w := l.current
+	l.mu.Unlock()
+
+	if w != nil {
+		return w.ConsumeQueue(queue, handler)
+	}
+	if _, err := l.conn(); err != nil {
+		l.retry()
+		return err
+	}
+	return nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Lazy: Ping (connects if needed)
+//||------------------------------------------------------------------------------------------------||
+
+func (l *LazyRabbitMQ) Ping() error {
+	w, err := l.conn()
+	if err != nil {
+		return err
+	}
+	return w.Ping()
+}
+
+// PingContext stops waiting at ctx's deadline; a dial it started keeps running for later callers.
+func (l *LazyRabbitMQ) PingContext(ctx context.Context) error {
+	errc := make(chan error, 1)
+	go func() { errc <- l.Ping() }()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Lazy: Close
+//||------------------------------------------------------------------------------------------------||
+
+func (l *LazyRabbitMQ) Close() error {
+	l.mu.Lock()
+	if !l.closed {
+		l.closed = true
+		close(l.closing)
+	}
+	w := l.current
+	l.current = nil
+	l.mu.Unlock()
+	if w == nil {
+		return nil
+	}
+	return w.Close()
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Helpers
+//||------------------------------------------------------------------------------------------------||
+
+func nextBackoff(cur time.Duration) time.Duration {
+	if cur <= 0 {
+		return lazyMinBackoff
+	}
+	cur *= 2
+	if cur > lazyMaxBackoff {
+		return lazyMaxBackoff
+	}
+	return cur
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Compile-time interface checks
+//||------------------------------------------------------------------------------------------------||
+
+var _ Queue = (*LazyRabbitMQ)(nil)
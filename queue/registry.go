@@ -0,0 +1,93 @@
+//||------------------------------------------------------------------------------------------------||
+//|| Queue Package: Backend Registry
+//|| registry.go
+//||------------------------------------------------------------------------------------------------||
+
+package queue
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ralphferrara/aria/config"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Factory: builds one named queue from its config block
+//||------------------------------------------------------------------------------------------------||
+
+type Factory func(name string, cfg config.QueueInstanceConfig) (Queue, error)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Registry
+//||------------------------------------------------------------------------------------------------||
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{}
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Register: add (or replace) a backend; names are case-insensitive
+//||------------------------------------------------------------------------------------------------||
+
+func Register(backend string, factory Factory) {
+	if backend == "" || factory == nil {
+		panic("queue: Register requires a backend name and factory")
+	}
+	registryMu.Lock()
+	registry[strings.ToLower(backend)] = factory
+	registryMu.Unlock()
+	config.RegisterBackend(config.SectionQueue, backend)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Backends: registered backend names (sorted)
+//||------------------------------------------------------------------------------------------------||
+
+func Backends() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	out := make([]string, 0, len(registry))
+	for name := range registry {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| lookup
+//||------------------------------------------------------------------------------------------------||
+
+func lookup(backend string) (Factory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	f, ok := registry[strings.ToLower(backend)]
+	return f, ok
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Built-in Backends
+//||------------------------------------------------------------------------------------------------||
+
+func init() {
+	Register("rabbitmq", newRabbitQueue)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| RabbitMQ
+//||------------------------------------------------------------------------------------------------||
+
+func newRabbitQueue(name string, cfg config.QueueInstanceConfig) (Queue, error) {
+	if cfg.Lazy {
+		return NewLazyRabbitMQ(name, cfg), nil
+	}
+	conn, ch, err := connectRabbit(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("rabbitmq connect failed: %w", err)
+	}
+	return &RabbitMQWrapper{Name: name, Conn: conn, Channel: ch}, nil
+}
@@ -6,6 +6,8 @@
 package queue
 
 import (
+	"context"
+
 	"github.com/streadway/amqp"
 )
 
@@ -19,6 +21,18 @@ const (
 	BackendRabbitMQ QueueBackend = "RABBITMQ"
 )
 
+//||------------------------------------------------------------------------------------------------||
+//|| Queue: Common Interface (implemented by every registered backend)
+//||------------------------------------------------------------------------------------------------||
+
+type Queue interface {
+	Publish(queue string, body []byte) error
+	ConsumeQueue(queue string, handler func([]byte)) error
+	Ping() error
+	PingContext(ctx context.Context) error
+	Close() error
+}
+
 //||------------------------------------------------------------------------------------------------||
 //|| RabbitMQWrapper: RabbitMQ Queue Wrapper
 //||------------------------------------------------------------------------------------------------||
@@ -28,3 +42,9 @@ type RabbitMQWrapper struct {
 	Conn    *amqp.Connection
 	Channel *amqp.Channel
 }
+
+//||------------------------------------------------------------------------------------------------||
+//|| Compile-time interface checks
+//||------------------------------------------------------------------------------------------------||
+
+var _ Queue = (*RabbitMQWrapper)(nil)
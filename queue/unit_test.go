@@ -0,0 +1,254 @@
+//||------------------------------------------------------------------------------------------------||
+//|| Queue Package: Unit Tests
+//|| unit_test.go
+//||------------------------------------------------------------------------------------------------||
+
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ralphferrara/aria/config"
+	"github.com/streadway/amqp"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| helper: in-memory connection that records consumers and can be dropped
+//||------------------------------------------------------------------------------------------------||
+
+type fakeConn struct {
+	mu       sync.Mutex
+	consumed []string
+	closed   chan *amqp.Error
+	dropped  bool
+}
+
+func newFakeConn() *fakeConn {
+	return &fakeConn{closed: make(chan *amqp.Error, 1)}
+}
+
+func (c *fakeConn) Publish(queue string, body []byte) error { return nil }
+
+func (c *fakeConn) ConsumeQueue(queue string, handler func([]byte)) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.consumed = append(c.consumed, queue)
+	return nil
+}
+
+func (c *fakeConn) Ping() error                           { return nil }
+func (c *fakeConn) PingContext(ctx context.Context) error { return nil }
+func (c *fakeConn) notifyClose() <-chan *amqp.Error       { return c.closed }
+
+func (c *fakeConn) Close() error {
+	c.drop()
+	return nil
+}
+
+// drop simulates the broker closing the connection.
+func (c *fakeConn) drop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.dropped {
+		c.dropped = true
+		c.closed <- amqp.ErrClosed
+		close(c.closed)
+	}
+}
+
+func (c *fakeConn) consumers() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.consumed...)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| helper: lazy queue whose dial is fn, counting calls
+//||------------------------------------------------------------------------------------------------||
+
+func newTestLazy(t *testing.T, fn func() (lazyConn, error)) (*LazyRabbitMQ, *atomic.Int32) {
+	t.Helper()
+	var dials atomic.Int32
+	l := NewLazyRabbitMQ("test", config.QueueInstanceConfig{})
+	l.dial = func() (lazyConn, error) {
+		dials.Add(1)
+		return fn()
+	}
+	t.Cleanup(func() { _ = l.Close() })
+	return l, &dials
+}
+
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Lazy Backoff: after a failed dial, callers fail fast with the last error
+//||------------------------------------------------------------------------------------------------||
+
+func TestLazy_FailFastWhileBackingOff(t *testing.T) {
+	refused := errors.New("connection refused")
+	l, dials := newTestLazy(t, func() (lazyConn, error) { return nil, refused })
+
+	if err := l.Publish("q", []byte("x")); !errors.Is(err, refused) {
+		t.Fatalf("first Publish = %v, want the dial error", err)
+	}
+	started := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := l.Publish("q", []byte("x")); !errors.Is(err, refused) {
+			t.Fatalf("Publish while backing off = %v, want the last dial error", err)
+		}
+	}
+	if time.Since(started) > 100*time.Millisecond {
+		t.Fatal("callers waited instead of failing fast")
+	}
+	if n := dials.Load(); n != 1 {
+		t.Fatalf("dials = %d, want 1 while backing off", n)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Lazy Dial: concurrent callers share one dial
+//||------------------------------------------------------------------------------------------------||
+
+func TestLazy_SingleDial(t *testing.T) {
+	release := make(chan struct{})
+	l, dials := newTestLazy(t, func() (lazyConn, error) {
+		<-release
+		return newFakeConn(), nil
+	})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- l.Publish("q", []byte("x"))
+		}()
+	}
+	eventually(t, "the first dial", func() bool { return dials.Load() == 1 })
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+	if n := dials.Load(); n != 1 {
+		t.Fatalf("dials = %d, want 1", n)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Lazy Consumers: a failed connect is returned, and consumers attach on every reconnect
+//||------------------------------------------------------------------------------------------------||
+
+func TestLazy_ReattachConsumers(t *testing.T) {
+	refused := errors.New("connection refused")
+	var mu sync.Mutex
+	var conns []*fakeConn
+	fail := true
+	l, _ := newTestLazy(t, func() (lazyConn, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if fail {
+			return nil, refused
+		}
+		c := newFakeConn()
+		conns = append(conns, c)
+		return c, nil
+	})
+	latest := func() *fakeConn {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(conns) == 0 {
+			return nil
+		}
+		return conns[len(conns)-1]
+	}
+
+	if err := l.ConsumeQueue("jobs", func([]byte) {}); !errors.Is(err, refused) {
+		t.Fatalf("ConsumeQueue = %v, want the dial error", err)
+	}
+
+	// the background retry attaches the registered consumer once the broker is back
+	mu.Lock()
+	fail = false
+	mu.Unlock()
+	eventually(t, "the first connection", func() bool { return latest() != nil })
+	first := latest()
+	eventually(t, "the consumer on the first connection", func() bool { return len(first.consumers()) == 1 })
+
+	// a dropped connection is replaced and the consumer attached again
+	first.drop()
+	eventually(t, "a reconnect", func() bool { return latest() != first })
+	second := latest()
+	eventually(t, "the consumer on the new connection", func() bool { return len(second.consumers()) == 1 })
+	if got := second.consumers(); got[0] != "jobs" {
+		t.Fatalf("re-attached consumers = %v", got)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Lazy Close: returns while a dial hangs; waiters are released with ErrQueueClosed
+//||------------------------------------------------------------------------------------------------||
+
+func TestLazy_CloseDuringHungDial(t *testing.T) {
+	release := make(chan struct{})
+	conn := newFakeConn()
+	l, dials := newTestLazy(t, func() (lazyConn, error) {
+		<-release
+		return conn, nil
+	})
+
+	dialer := make(chan error, 1)
+	go func() { dialer <- l.Publish("q", []byte("x")) }()
+	eventually(t, "the dial", func() bool { return dials.Load() == 1 })
+	waiter := make(chan error, 1)
+	go func() { waiter <- l.Publish("q", []byte("x")) }()
+
+	closed := make(chan error, 1)
+	go func() { closed <- l.Close() }()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close blocked on the hung dial")
+	}
+	select {
+	case err := <-waiter:
+		if !errors.Is(err, ErrQueueClosed) {
+			t.Fatalf("waiting caller = %v, want ErrQueueClosed", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("waiting caller was not released by Close")
+	}
+
+	// the dial that finishes after Close is discarded
+	close(release)
+	if err := <-dialer; !errors.Is(err, ErrQueueClosed) {
+		t.Fatalf("dialing caller = %v, want ErrQueueClosed", err)
+	}
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	if !conn.dropped {
+		t.Fatal("connection dialed after Close was not closed")
+	}
+}
@@ -0,0 +1,45 @@
+package responses
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/ralphferrara/aria/app"
+	ariahttp "github.com/ralphferrara/aria/http"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| AppError: render err as JSON; *app.Error uses its status and the request language
+//||------------------------------------------------------------------------------------------------||
+
+func AppError(w http.ResponseWriter, r *http.Request, err error) {
+	var appErr *app.Error
+	if !errors.As(err, &appErr) {
+		Error(w, http.StatusInternalServerError, "Internal server error")
+		log.Printf("❌ unhandled error: %v\n", err)
+		return
+	}
+
+	status := appErr.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	if appErr.Cause != nil {
+		log.Printf("❌ %d %s: %v\n", status, appErr.Key(), appErr.Cause)
+	}
+
+	resp := map[string]any{
+		"success": false,
+		"code":    appErr.Key(),
+		"message": appErr.Localize(ariahttp.GetClientLanguage(r)),
+	}
+	if len(appErr.Details) > 0 {
+		resp["details"] = appErr.Details
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
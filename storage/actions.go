@@ -1,36 +1,245 @@
 package storage
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"io"
+)
 
 //||------------------------------------------------------------------------------------------------||
 //|| Put
 //||------------------------------------------------------------------------------------------------||
 
-func (s *Storage) Put(objectName string, data []byte) error {
+func (s *Storage) Put(ctx context.Context, objectName string, data []byte, opts ...PutOptions) error {
 	if s.service == nil {
 		return fmt.Errorf("storage backend not initialized")
 	}
-	return s.service.Put(objectName, data)
+	objectName, err := NormalizeKey(objectName)
+	if err != nil {
+		return err
+	}
+	return s.do(ctx, func(ctx context.Context) error {
+		return s.service.Put(ctx, objectName, data, opts...)
+	})
 }
 
 //||------------------------------------------------------------------------------------------------||
 //|| Get
 //||------------------------------------------------------------------------------------------------||
 
-func (s *Storage) Get(objectName string) ([]byte, error) {
+func (s *Storage) Get(ctx context.Context, objectName string) ([]byte, error) {
 	if s.service == nil {
 		return nil, fmt.Errorf("storage backend not initialized")
 	}
-	return s.service.Get(objectName)
+	objectName, err := NormalizeKey(objectName)
+	if err != nil {
+		return nil, err
+	}
+	var data []byte
+	err = s.do(ctx, func(ctx context.Context) error {
+		var err error
+		data, err = s.service.Get(ctx, objectName)
+		return err
+	})
+	return data, err
 }
 
 //||------------------------------------------------------------------------------------------------||
 //|| Delete
 //||------------------------------------------------------------------------------------------------||
 
-func (s *Storage) Delete(objectName string) error {
+func (s *Storage) Delete(ctx context.Context, objectName string) error {
 	if s.service == nil {
 		return fmt.Errorf("storage backend not initialized")
 	}
-	return s.service.Delete(objectName)
+	objectName, err := NormalizeKey(objectName)
+	if err != nil {
+		return err
+	}
+	return s.do(ctx, func(ctx context.Context) error {
+		return s.service.Delete(ctx, objectName)
+	})
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| PutStream: upload from r without buffering (size may be SizeUnknown)
+//||
+//|| The timeout is an idle limit here: it restarts whenever the backend reads from r, so a long
+//|| upload is only cut off once it stalls. Retried only when r is an io.Seeker (it is rewound to
+//|| where it started between attempts).
+//||------------------------------------------------------------------------------------------------||
+
+func (s *Storage) PutStream(ctx context.Context, objectName string, r io.Reader, size int64, opts PutOptions) error {
+	if s.service == nil {
+		return fmt.Errorf("storage backend not initialized")
+	}
+	objectName, err := NormalizeKey(objectName)
+	if err != nil {
+		return err
+	}
+	ctx, idle := s.withIdleTimeout(ctx)
+	defer idle.stop()
+	body := watchProgress(r, idle)
+	seeker, ok := r.(io.Seeker)
+	if !ok {
+		return idle.err(s.service.PutStream(ctx, objectName, body, size, opts))
+	}
+	start, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	first := true
+	err = s.retry(ctx, func(ctx context.Context) error {
+		if !first {
+			if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+				return err
+			}
+		}
+		first = false
+		idle.touch()
+		return s.service.PutStream(ctx, objectName, body, size, opts)
+	})
+	return idle.err(err)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| GetStream: caller must Close the reader (the timeout covers opening, not reading)
+//||------------------------------------------------------------------------------------------------||
+
+func (s *Storage) GetStream(ctx context.Context, objectName string) (io.ReadCloser, ObjectInfo, error) {
+	if s.service == nil {
+		return nil, ObjectInfo{}, fmt.Errorf("storage backend not initialized")
+	}
+	objectName, err := NormalizeKey(objectName)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	var info ObjectInfo
+	rc, err := s.openStream(ctx, func(ctx context.Context) (io.ReadCloser, error) {
+		rc, i, err := s.service.GetStream(ctx, objectName)
+		info = i
+		return rc, err
+	})
+	return rc, info, err
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| GetRange: length bytes from offset (ToEnd reads the rest); caller must Close the reader
+//||------------------------------------------------------------------------------------------------||
+
+func (s *Storage) GetRange(ctx context.Context, objectName string, offset, length int64) (io.ReadCloser, error) {
+	if s.service == nil {
+		return nil, fmt.Errorf("storage backend not initialized")
+	}
+	objectName, err := NormalizeKey(objectName)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRange(offset, length); err != nil {
+		return nil, err
+	}
+	return s.openStream(ctx, func(ctx context.Context) (io.ReadCloser, error) {
+		return s.service.GetRange(ctx, objectName, offset, length)
+	})
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Stat: errors.Is(err, ErrNotFound) when the object is missing
+//||------------------------------------------------------------------------------------------------||
+
+func (s *Storage) Stat(ctx context.Context, objectName string) (ObjectInfo, error) {
+	if s.service == nil {
+		return ObjectInfo{}, fmt.Errorf("storage backend not initialized")
+	}
+	objectName, err := NormalizeKey(objectName)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	var info ObjectInfo
+	err = s.do(ctx, func(ctx context.Context) error {
+		var err error
+		info, err = s.service.Stat(ctx, objectName)
+		return err
+	})
+	return info, err
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Exists
+//||------------------------------------------------------------------------------------------------||
+
+func (s *Storage) Exists(ctx context.Context, objectName string) (bool, error) {
+	if s.service == nil {
+		return false, fmt.Errorf("storage backend not initialized")
+	}
+	objectName, err := NormalizeKey(objectName)
+	if err != nil {
+		return false, err
+	}
+	var ok bool
+	err = s.do(ctx, func(ctx context.Context) error {
+		var err error
+		ok, err = s.service.Exists(ctx, objectName)
+		return err
+	})
+	return ok, err
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| List: one page of objects under prefix; pass NextCursor back until it is ""
+//||------------------------------------------------------------------------------------------------||
+
+func (s *Storage) List(ctx context.Context, prefix, cursor string) (ListPage, error) {
+	if s.service == nil {
+		return ListPage{}, fmt.Errorf("storage backend not initialized")
+	}
+	prefix, err := normalizePrefix(prefix)
+	if err != nil {
+		return ListPage{}, err
+	}
+	var page ListPage
+	err = s.do(ctx, func(ctx context.Context) error {
+		var err error
+		page, err = s.service.List(ctx, prefix, cursor)
+		return err
+	})
+	return page, err
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Copy / Move (server-side where the backend supports it)
+//||------------------------------------------------------------------------------------------------||
+
+func (s *Storage) Copy(ctx context.Context, src, dst string) error {
+	if s.service == nil {
+		return fmt.Errorf("storage backend not initialized")
+	}
+	src, err := NormalizeKey(src)
+	if err != nil {
+		return err
+	}
+	dst, err = NormalizeKey(dst)
+	if err != nil {
+		return err
+	}
+	return s.do(ctx, func(ctx context.Context) error {
+		return s.service.Copy(ctx, src, dst)
+	})
+}
+
+func (s *Storage) Move(ctx context.Context, src, dst string) error {
+	if s.service == nil {
+		return fmt.Errorf("storage backend not initialized")
+	}
+	src, err := NormalizeKey(src)
+	if err != nil {
+		return err
+	}
+	dst, err = NormalizeKey(dst)
+	if err != nil {
+		return err
+	}
+	return s.do(ctx, func(ctx context.Context) error {
+		return s.service.Move(ctx, src, dst)
+	})
 }
@@ -5,9 +5,16 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
 )
 
 //||------------------------------------------------------------------------------------------------||
@@ -57,11 +64,14 @@ func NewAzureBackend(cfg StoreConfig) (*StorageEngineAzure, error) {
 //|| Put: Upload an object
 //||------------------------------------------------------------------------------------------------||
 
-func (a *StorageEngineAzure) Put(objectName string, data []byte) error {
-	ctx := context.Background()
+func (a *StorageEngineAzure) Put(ctx context.Context, objectName string, data []byte, opts ...PutOptions) error {
+	o := putOptions(opts)
 	blobClient := a.containerClient.NewBlockBlobClient(objectName)
 	rsc := newReadSeekCloser(data)
-	_, err := blobClient.Upload(ctx, rsc, nil)
+	_, err := blobClient.Upload(ctx, rsc, &blockblob.UploadOptions{
+		HTTPHeaders: azureHeaders(objectName, o),
+		Metadata:    azureMetadata(o.Metadata),
+	})
 	return err
 }
 
@@ -69,12 +79,11 @@ func (a *StorageEngineAzure) Put(objectName string, data []byte) error {
 //|| Get: Download an object
 //||------------------------------------------------------------------------------------------------||
 
-func (a *StorageEngineAzure) Get(objectName string) ([]byte, error) {
-	ctx := context.Background()
+func (a *StorageEngineAzure) Get(ctx context.Context, objectName string) ([]byte, error) {
 	blobClient := a.containerClient.NewBlockBlobClient(objectName)
 	resp, err := blobClient.DownloadStream(ctx, nil)
 	if err != nil {
-		return nil, err
+		return nil, azureNotExist(objectName, err)
 	}
 	defer resp.Body.Close()
 	buf := new(bytes.Buffer)
@@ -89,9 +98,251 @@ func (a *StorageEngineAzure) Get(objectName string) ([]byte, error) {
 //|| Delete: Delete an object
 //||------------------------------------------------------------------------------------------------||
 
-func (a *StorageEngineAzure) Delete(objectName string) error {
-	ctx := context.Background()
+func (a *StorageEngineAzure) Delete(ctx context.Context, objectName string) error {
 	blobClient := a.containerClient.NewBlockBlobClient(objectName)
 	_, err := blobClient.Delete(ctx, nil)
 	return err
 }
+
+//||------------------------------------------------------------------------------------------------||
+//|| PutStream: Upload from a reader in blocks (size is not needed)
+//||------------------------------------------------------------------------------------------------||
+
+func (a *StorageEngineAzure) PutStream(ctx context.Context, objectName string, r io.Reader, size int64, opts PutOptions) error {
+	blobClient := a.containerClient.NewBlockBlobClient(objectName)
+	_, err := blobClient.UploadStream(ctx, r, &blockblob.UploadStreamOptions{
+		HTTPHeaders: azureHeaders(objectName, opts),
+		Metadata:    azureMetadata(opts.Metadata),
+	})
+	return err
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| GetStream: Download as a stream
+//||------------------------------------------------------------------------------------------------||
+
+func (a *StorageEngineAzure) GetStream(ctx context.Context, objectName string) (io.ReadCloser, ObjectInfo, error) {
+	resp, err := a.containerClient.NewBlockBlobClient(objectName).DownloadStream(ctx, nil)
+	if err != nil {
+		return nil, ObjectInfo{}, azureNotExist(objectName, err)
+	}
+	info := ObjectInfo{Name: objectName}
+	if resp.ContentLength != nil {
+		info.Size = *resp.ContentLength
+	}
+	if resp.ContentType != nil {
+		info.ContentType = *resp.ContentType
+	}
+	if resp.ETag != nil {
+		info.ETag = strings.Trim(string(*resp.ETag), `"`)
+	}
+	if resp.LastModified != nil {
+		info.LastModified = *resp.LastModified
+	}
+	return resp.Body, info, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| GetRange: Download part of a blob (Count 0 = to the end)
+//||------------------------------------------------------------------------------------------------||
+
+func (a *StorageEngineAzure) GetRange(ctx context.Context, objectName string, offset, length int64) (io.ReadCloser, error) {
+	if err := checkRange(offset, length); err != nil {
+		return nil, err
+	}
+	rng := blob.HTTPRange{Offset: offset}
+	if length != ToEnd {
+		rng.Count = length
+	}
+	resp, err := a.containerClient.NewBlockBlobClient(objectName).DownloadStream(ctx, &blob.DownloadStreamOptions{Range: rng})
+	if err != nil {
+		return nil, azureNotExist(objectName, err)
+	}
+	return resp.Body, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Stat: GetProperties
+//||------------------------------------------------------------------------------------------------||
+
+func (a *StorageEngineAzure) Stat(ctx context.Context, objectName string) (ObjectInfo, error) {
+	props, err := a.containerClient.NewBlobClient(objectName).GetProperties(ctx, nil)
+	if err != nil {
+		return ObjectInfo{}, azureNotExist(objectName, err)
+	}
+	info := ObjectInfo{Name: objectName, Metadata: map[string]string{}}
+	if props.ContentLength != nil {
+		info.Size = *props.ContentLength
+	}
+	if props.ContentType != nil {
+		info.ContentType = *props.ContentType
+	}
+	if props.CacheControl != nil {
+		info.CacheControl = *props.CacheControl
+	}
+	if props.ETag != nil {
+		info.ETag = strings.Trim(string(*props.ETag), `"`)
+	}
+	if props.LastModified != nil {
+		info.LastModified = *props.LastModified
+	}
+	for k, v := range props.Metadata {
+		if v != nil {
+			info.Metadata[k] = *v
+		}
+	}
+	return info, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Exists
+//||------------------------------------------------------------------------------------------------||
+
+func (a *StorageEngineAzure) Exists(ctx context.Context, objectName string) (bool, error) {
+	return exists(a.Stat(ctx, objectName))
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| List: one flat-listing page (cursor is the service's marker)
+//||------------------------------------------------------------------------------------------------||
+
+func (a *StorageEngineAzure) List(ctx context.Context, prefix, cursor string) (ListPage, error) {
+	opts := &container.ListBlobsFlatOptions{MaxResults: to.Ptr(int32(ListPageSize))}
+	if prefix != "" {
+		opts.Prefix = &prefix
+	}
+	if cursor != "" {
+		opts.Marker = &cursor
+	}
+	resp, err := a.containerClient.NewListBlobsFlatPager(opts).NextPage(ctx)
+	if err != nil {
+		return ListPage{}, err
+	}
+	page := ListPage{}
+	for _, item := range resp.Segment.BlobItems {
+		info := ObjectInfo{Name: *item.Name}
+		if p := item.Properties; p != nil {
+			if p.ContentLength != nil {
+				info.Size = *p.ContentLength
+			}
+			if p.ContentType != nil {
+				info.ContentType = *p.ContentType
+			}
+			if p.CacheControl != nil {
+				info.CacheControl = *p.CacheControl
+			}
+			if p.ETag != nil {
+				info.ETag = strings.Trim(string(*p.ETag), `"`)
+			}
+			if p.LastModified != nil {
+				info.LastModified = *p.LastModified
+			}
+		}
+		page.Objects = append(page.Objects, info)
+	}
+	if resp.NextMarker != nil {
+		page.NextCursor = *resp.NextMarker
+	}
+	return page, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Copy: server-side copy within the account, waiting for it to finish
+//||------------------------------------------------------------------------------------------------||
+
+func (a *StorageEngineAzure) Copy(ctx context.Context, src, dst string) error {
+	dstClient := a.containerClient.NewBlobClient(dst)
+	resp, err := dstClient.StartCopyFromURL(ctx, a.containerClient.NewBlobClient(src).URL(), nil)
+	if err != nil {
+		return err
+	}
+	status := resp.CopyStatus
+	for status != nil && *status == blob.CopyStatusTypePending {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(250 * time.Millisecond):
+		}
+		props, err := dstClient.GetProperties(ctx, nil)
+		if err != nil {
+			return err
+		}
+		status = props.CopyStatus
+	}
+	if status != nil && *status != blob.CopyStatusTypeSuccess {
+		return fmt.Errorf("copy %s -> %s: %s", src, dst, *status)
+	}
+	return nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Move: Copy then Delete
+//||------------------------------------------------------------------------------------------------||
+
+func (a *StorageEngineAzure) Move(ctx context.Context, src, dst string) error {
+	if err := a.Copy(ctx, src, dst); err != nil {
+		return err
+	}
+	_, err := a.containerClient.NewBlobClient(src).Delete(ctx, nil)
+	return err
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| PresignGet / PresignPut: service SAS signed with the account key
+//||
+//|| Uploads must send "x-ms-blob-type: BlockBlob". SAS cannot bind Content-Type or size.
+//||------------------------------------------------------------------------------------------------||
+
+func (a *StorageEngineAzure) PresignGet(ctx context.Context, objectName string, ttl time.Duration) (string, error) {
+	return a.containerClient.NewBlobClient(objectName).GetSASURL(sas.BlobPermissions{Read: true}, time.Now().Add(ttl), nil)
+}
+
+func (a *StorageEngineAzure) PresignPut(ctx context.Context, objectName string, ttl time.Duration, c PresignConstraints) (string, error) {
+	if !c.empty() {
+		return "", fmt.Errorf("azure SAS URLs cannot enforce content type or length")
+	}
+	return a.containerClient.NewBlobClient(objectName).GetSASURL(sas.BlobPermissions{Create: true, Write: true}, time.Now().Add(ttl), nil)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Azure Helpers
+//||------------------------------------------------------------------------------------------------||
+
+func azureHeaders(objectName string, opts PutOptions) *blob.HTTPHeaders {
+	h := &blob.HTTPHeaders{BlobContentType: to.Ptr(contentType(objectName, opts.ContentType))}
+	if opts.CacheControl != "" {
+		h.BlobCacheControl = to.Ptr(opts.CacheControl)
+	}
+	return h
+}
+
+func azureMetadata(m map[string]string) map[string]*string {
+	if len(m) == 0 {
+		return nil
+	}
+	out := make(map[string]*string, len(m))
+	for k, v := range m {
+		out[k] = to.Ptr(v)
+	}
+	return out
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Ping: Check the container is reachable (no writes)
+//||------------------------------------------------------------------------------------------------||
+
+func (a *StorageEngineAzure) Ping(ctx context.Context) error {
+	_, err := a.containerClient.GetProperties(ctx, nil)
+	return err
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| azureNotExist: map BlobNotFound to ErrNotFound
+//||------------------------------------------------------------------------------------------------||
+
+func azureNotExist(objectName string, err error) error {
+	if bloberror.HasCode(err, bloberror.BlobNotFound) {
+		return notFound(objectName)
+	}
+	return err
+}
@@ -1,5 +1,7 @@
 package storage
 
+import "time"
+
 //||------------------------------------------------------------------------------------------------||
 //|| Backend
 //||------------------------------------------------------------------------------------------------||
@@ -46,9 +48,20 @@ type StoreConfig struct {
 	//|| Local Storage Specific
 	//||------------------------------------------------------------------------------------------------||
 	LocalPath string `json:"local_path,omitempty"`
+	PublicURL string `json:"public_url,omitempty"` // where Storage.Handler is mounted (presigned URLs)
+	SignKey   string `json:"sign_key,omitempty"`   // HMAC key for presigned URLs
 	//||------------------------------------------------------------------------------------------------||
 	//|| Optional
 	//||------------------------------------------------------------------------------------------------||
 	UseSSL    bool `json:"use_ssl,omitempty"`
 	PathStyle bool `json:"path_style,omitempty"`
+	// ProbeWrite makes Ping write, read back and delete a test object
+	// instead of only checking the bucket. Off by default: it writes
+	// into production buckets.
+	ProbeWrite bool `json:"probe_write,omitempty"`
+	//||------------------------------------------------------------------------------------------------||
+	//|| Timeouts / Retries
+	//||------------------------------------------------------------------------------------------------||
+	Timeout time.Duration `json:"timeout,omitempty"` // per call when ctx has no deadline; streams: to open, or without progress (0 = DefaultTimeout)
+	Retry   RetryPolicy   `json:"retry,omitempty"`
 }
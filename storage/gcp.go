@@ -3,13 +3,16 @@ package storage
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 
 	"cloud.google.com/go/storage"
-	"github.com/ralphferrara/aria/log"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
 
@@ -32,13 +35,8 @@ func NewGCPBackend(cfg StoreConfig) (*StorageEngineGCP, error) {
 	var client *storage.Client
 	var err error
 
-	log.PrettyPrint(cfg)
-
+	// credentials may be a file path or the JSON itself, so neither is ever logged
 	if cfg.CredentialsJSON != "" {
-		fmt.Println("[GCP] - Credentials Path:", cfg.CredentialsJSON)
-		if _, err := os.Stat(cfg.CredentialsJSON); err != nil {
-			fmt.Println("[GCP] - File not found:", err)
-		}
 		if _, statErr := os.Stat(cfg.CredentialsJSON); statErr == nil {
 			absPath, _ := filepath.Abs(cfg.CredentialsJSON)
 			creds, readErr := os.ReadFile(absPath)
@@ -69,26 +67,18 @@ func NewGCPBackend(cfg StoreConfig) (*StorageEngineGCP, error) {
 //|| Put: Upload an object
 //||------------------------------------------------------------------------------------------------||
 
-func (g *StorageEngineGCP) Put(objectName string, data []byte) error {
-	ctx := context.Background()
-	wc := g.client.Bucket(g.bucket).Object(objectName).NewWriter(ctx)
-	_, err := wc.Write(data)
-	if err != nil {
-		_ = wc.Close()
-		return err
-	}
-	return wc.Close()
+func (g *StorageEngineGCP) Put(ctx context.Context, objectName string, data []byte, opts ...PutOptions) error {
+	return g.upload(ctx, objectName, bytes.NewReader(data), int64(len(data)), putOptions(opts))
 }
 
 //||------------------------------------------------------------------------------------------------||
 //|| Get: Download an object
 //||------------------------------------------------------------------------------------------------||
 
-func (g *StorageEngineGCP) Get(objectName string) ([]byte, error) {
-	ctx := context.Background()
+func (g *StorageEngineGCP) Get(ctx context.Context, objectName string) ([]byte, error) {
 	rc, err := g.client.Bucket(g.bucket).Object(objectName).NewReader(ctx)
 	if err != nil {
-		return nil, err
+		return nil, gcpNotExist(objectName, err)
 	}
 	defer rc.Close()
 
@@ -104,7 +94,204 @@ func (g *StorageEngineGCP) Get(objectName string) ([]byte, error) {
 //|| Delete: Delete an object
 //||------------------------------------------------------------------------------------------------||
 
-func (g *StorageEngineGCP) Delete(objectName string) error {
-	ctx := context.Background()
+func (g *StorageEngineGCP) Delete(ctx context.Context, objectName string) error {
 	return g.client.Bucket(g.bucket).Object(objectName).Delete(ctx)
 }
+
+//||------------------------------------------------------------------------------------------------||
+//|| PutStream: Upload from a reader (resumable upload in chunks)
+//||------------------------------------------------------------------------------------------------||
+
+func (g *StorageEngineGCP) PutStream(ctx context.Context, objectName string, r io.Reader, size int64, opts PutOptions) error {
+	return g.upload(ctx, objectName, r, size, opts)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| upload: Close commits a GCS upload, so on any error the writer's context is cancelled first
+//|| (aborting it) and the previous object stays in place (a known size must match what was read)
+//||------------------------------------------------------------------------------------------------||
+
+func (g *StorageEngineGCP) upload(ctx context.Context, objectName string, r io.Reader, size int64, opts PutOptions) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	wc := g.writer(ctx, objectName, opts)
+	n, err := io.Copy(wc, r)
+	if err == nil && size >= 0 && n != size {
+		err = fmt.Errorf("short write: got %d bytes, want %d", n, size)
+	}
+	if err != nil {
+		cancel()
+		_ = wc.Close()
+		return err
+	}
+	return wc.Close()
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| GetStream: Download as a stream
+//||------------------------------------------------------------------------------------------------||
+
+func (g *StorageEngineGCP) GetStream(ctx context.Context, objectName string) (io.ReadCloser, ObjectInfo, error) {
+	rc, err := g.client.Bucket(g.bucket).Object(objectName).NewReader(ctx)
+	if err != nil {
+		return nil, ObjectInfo{}, gcpNotExist(objectName, err)
+	}
+	return rc, ObjectInfo{
+		Name:         objectName,
+		Size:         rc.Attrs.Size,
+		ContentType:  rc.Attrs.ContentType,
+		LastModified: rc.Attrs.LastModified,
+	}, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| GetRange: Download part of an object (length -1 = to the end)
+//||------------------------------------------------------------------------------------------------||
+
+func (g *StorageEngineGCP) GetRange(ctx context.Context, objectName string, offset, length int64) (io.ReadCloser, error) {
+	if err := checkRange(offset, length); err != nil {
+		return nil, err
+	}
+	rc, err := g.client.Bucket(g.bucket).Object(objectName).NewRangeReader(ctx, offset, length)
+	if err != nil {
+		return nil, gcpNotExist(objectName, err)
+	}
+	return rc, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| writer: object writer carrying PutOptions
+//||------------------------------------------------------------------------------------------------||
+
+func (g *StorageEngineGCP) writer(ctx context.Context, objectName string, opts PutOptions) *storage.Writer {
+	wc := g.client.Bucket(g.bucket).Object(objectName).NewWriter(ctx)
+	wc.ContentType = contentType(objectName, opts.ContentType)
+	wc.CacheControl = opts.CacheControl
+	wc.Metadata = opts.Metadata
+	return wc
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Stat: object attributes
+//||------------------------------------------------------------------------------------------------||
+
+func (g *StorageEngineGCP) Stat(ctx context.Context, objectName string) (ObjectInfo, error) {
+	attrs, err := g.client.Bucket(g.bucket).Object(objectName).Attrs(ctx)
+	if err != nil {
+		return ObjectInfo{}, gcpNotExist(objectName, err)
+	}
+	info := gcpInfo(attrs)
+	info.Metadata = attrs.Metadata
+	return info, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Exists
+//||------------------------------------------------------------------------------------------------||
+
+func (g *StorageEngineGCP) Exists(ctx context.Context, objectName string) (bool, error) {
+	return exists(g.Stat(ctx, objectName))
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| List: one iterator page (cursor is the page token)
+//||------------------------------------------------------------------------------------------------||
+
+func (g *StorageEngineGCP) List(ctx context.Context, prefix, cursor string) (ListPage, error) {
+	it := g.client.Bucket(g.bucket).Objects(ctx, &storage.Query{Prefix: prefix})
+	var attrs []*storage.ObjectAttrs
+	next, err := iterator.NewPager(it, ListPageSize, cursor).NextPage(&attrs)
+	if err != nil {
+		return ListPage{}, err
+	}
+	page := ListPage{Objects: make([]ObjectInfo, 0, len(attrs)), NextCursor: next}
+	for _, a := range attrs {
+		page.Objects = append(page.Objects, gcpInfo(a))
+	}
+	return page, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Copy: server-side rewrite (metadata is copied with the object)
+//||------------------------------------------------------------------------------------------------||
+
+func (g *StorageEngineGCP) Copy(ctx context.Context, src, dst string) error {
+	b := g.client.Bucket(g.bucket)
+	_, err := b.Object(dst).CopierFrom(b.Object(src)).Run(ctx)
+	return err
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Move: Copy then Delete
+//||------------------------------------------------------------------------------------------------||
+
+func (g *StorageEngineGCP) Move(ctx context.Context, src, dst string) error {
+	if err := g.Copy(ctx, src, dst); err != nil {
+		return err
+	}
+	return g.client.Bucket(g.bucket).Object(src).Delete(ctx)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| PresignGet / PresignPut: V4 signed URLs (signer taken from the client's credentials)
+//||------------------------------------------------------------------------------------------------||
+
+func (g *StorageEngineGCP) PresignGet(ctx context.Context, objectName string, ttl time.Duration) (string, error) {
+	return g.client.Bucket(g.bucket).SignedURL(objectName, &storage.SignedURLOptions{
+		Method:  http.MethodGet,
+		Expires: time.Now().Add(ttl),
+		Scheme:  storage.SigningSchemeV4,
+	})
+}
+
+func (g *StorageEngineGCP) PresignPut(ctx context.Context, objectName string, ttl time.Duration, c PresignConstraints) (string, error) {
+	opts := &storage.SignedURLOptions{
+		Method:      http.MethodPut,
+		Expires:     time.Now().Add(ttl),
+		Scheme:      storage.SigningSchemeV4,
+		ContentType: c.ContentType,
+	}
+	if c.ContentLength > 0 {
+		opts.Headers = []string{fmt.Sprintf("x-goog-content-length-range:%d,%d", c.ContentLength, c.ContentLength)}
+	}
+	return g.client.Bucket(g.bucket).SignedURL(objectName, opts)
+}
+
+func gcpInfo(a *storage.ObjectAttrs) ObjectInfo {
+	return ObjectInfo{
+		Name:         a.Name,
+		Size:         a.Size,
+		ContentType:  a.ContentType,
+		CacheControl: a.CacheControl,
+		ETag:         a.Etag,
+		LastModified: a.Updated,
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Close: release the client's connections
+//||------------------------------------------------------------------------------------------------||
+
+func (g *StorageEngineGCP) Close() error {
+	return g.client.Close()
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Ping: Check the bucket is reachable (no writes)
+//||------------------------------------------------------------------------------------------------||
+
+func (g *StorageEngineGCP) Ping(ctx context.Context) error {
+	_, err := g.client.Bucket(g.bucket).Attrs(ctx)
+	return err
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| gcpNotExist: map ErrObjectNotExist to ErrNotFound
+//||------------------------------------------------------------------------------------------------||
+
+func gcpNotExist(objectName string, err error) error {
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return notFound(objectName)
+	}
+	return err
+}
@@ -1,8 +1,9 @@
 package storage
 
 import (
+	"context"
 	"fmt"
-	"strings"
+	"io"
 
 	"github.com/ralphferrara/aria/config"
 )
@@ -12,9 +13,17 @@ import (
 //||------------------------------------------------------------------------------------------------||
 
 type StoreService interface {
-	Put(objectName string, data []byte) error
-	Get(objectName string) ([]byte, error)
-	Delete(objectName string) error
+	Put(ctx context.Context, objectName string, data []byte, opts ...PutOptions) error
+	Get(ctx context.Context, objectName string) ([]byte, error)
+	Delete(ctx context.Context, objectName string) error
+	PutStream(ctx context.Context, objectName string, r io.Reader, size int64, opts PutOptions) error
+	GetStream(ctx context.Context, objectName string) (io.ReadCloser, ObjectInfo, error)
+	GetRange(ctx context.Context, objectName string, offset, length int64) (io.ReadCloser, error)
+	Stat(ctx context.Context, objectName string) (ObjectInfo, error)
+	Exists(ctx context.Context, objectName string) (bool, error)
+	List(ctx context.Context, prefix, cursor string) (ListPage, error)
+	Copy(ctx context.Context, src, dst string) error
+	Move(ctx context.Context, src, dst string) error
 }
 
 //||------------------------------------------------------------------------------------------------||
@@ -43,7 +52,8 @@ func Init(cfg *config.Config) (map[string]*Storage, error) {
 		st := &Storage{Config: storeCfg}
 
 		if err := st.InitStorage(); err != nil {
-			return nil, fmt.Errorf("storage '%s' init failed: %w", name, err)
+			closeAll(storages)
+			return nil, &config.InstanceError{Section: config.SectionStorage, Name: name, Err: err}
 		}
 
 		fmt.Printf("\n[STRG] - Initializing storage: %s (backend: %s)", name, storeCfg.Backend)
@@ -57,62 +67,42 @@ func Init(cfg *config.Config) (map[string]*Storage, error) {
 }
 
 //||------------------------------------------------------------------------------------------------||
-//|| Init (Selects backend implementation)
+//|| InitStorage (Selects backend implementation from the registry)
 //||------------------------------------------------------------------------------------------------||
 
 func (s *Storage) InitStorage() error {
-	switch strings.ToUpper(string(s.Config.Backend)) {
-	//||------------------------------------------------------------------------------------------------||
-	//|| S3
-	//||------------------------------------------------------------------------------------------------||
-	case strings.ToUpper(string(StorageS3)):
-		s3svc, err := NewS3Backend(s.Config)
-		if err != nil {
-			return fmt.Errorf("S3 backend init failed: %w", err)
-		}
-		s.service = s3svc
-		return nil
-	//||------------------------------------------------------------------------------------------------||
-	//|| Min.io
-	//||------------------------------------------------------------------------------------------------||
-	case strings.ToUpper(string(StorageMinIO)):
-		minioSvc, err := NewMinioBackend(s.Config)
-		if err != nil {
-			return fmt.Errorf("MinIO backend init failed: %w", err)
-		}
-		s.service = minioSvc
-		return nil
-	//||------------------------------------------------------------------------------------------------||
-	//|| Azure
-	//||------------------------------------------------------------------------------------------------||
-	case strings.ToUpper(string(StorageAzure)):
-		azureSvc, err := NewAzureBackend(s.Config)
-		if err != nil {
-			return fmt.Errorf("Azure backend init failed: %w", err)
-		}
-		s.service = azureSvc
+	factory, ok := lookup(s.Config.Backend)
+	if !ok {
+		return fmt.Errorf("unsupported storage backend: %s", s.Config.Backend)
+	}
+	svc, err := factory(s.Config)
+	if err != nil {
+		return err
+	}
+	s.service = svc
+	return nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Close: release what the backend holds open (the local root, the GCS client)
+//||------------------------------------------------------------------------------------------------||
+
+func (s *Storage) Close() error {
+	if s == nil || s.service == nil {
 		return nil
-	//||------------------------------------------------------------------------------------------------||
-	//|| BackendGCP
-	//||------------------------------------------------------------------------------------------------||
-	case strings.ToUpper(string(StorageGCP)):
-		gcpSvc, err := NewGCPBackend(s.Config)
-		if err != nil {
-			return fmt.Errorf("GCP backend init failed: %w", err)
+	}
+	if c, ok := s.service.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// closeAll closes storages built before a later instance failed to initialize.
+func closeAll(storages map[string]*Storage) {
+	for _, st := range storages {
+		if DefaultStorage == st {
+			DefaultStorage = nil
 		}
-		s.service = gcpSvc
-		return nil
-	//||------------------------------------------------------------------------------------------------||
-	//|| Local
-	//||------------------------------------------------------------------------------------------------||
-	case strings.ToUpper(string(StorageLocal)):
-		localSvc := NewLocalBackend(s.Config)
-		s.service = localSvc
-		return nil
-	//||------------------------------------------------------------------------------------------------||
-	//|| Fail
-	//||------------------------------------------------------------------------------------------------||
-	default:
-		return fmt.Errorf("unsupported storage backend: %s", s.Config.Backend)
+		_ = st.Close()
 	}
 }
@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Object Keys: one policy for every backend
+//||
+//||   - leading slashes are dropped and the key is put in Unicode NFC, so "/a/é" and "a/é"
+//||     (either way é is encoded) name the same object everywhere
+//||   - at most MaxKeyLength bytes of valid UTF-8, no control characters and no backslashes
+//||   - "/"-separated segments, none empty, "." or ".."
+//||   - the first segment may not be one the local backend keeps for itself (.aria-meta, .aria-tmp)
+//||------------------------------------------------------------------------------------------------||
+
+const MaxKeyLength = 1024 // S3, GCS and Azure all stop at 1024
+
+var ErrInvalidKey = errors.New("invalid object key")
+
+var reservedKeyRoots = []string{localMetaDir, localTempDir}
+
+//||------------------------------------------------------------------------------------------------||
+//|| NormalizeKey: the canonical form of objectName, or an error wrapping ErrInvalidKey
+//||------------------------------------------------------------------------------------------------||
+
+func NormalizeKey(objectName string) (string, error) {
+	key, err := normalize(objectName)
+	if err == nil && key == "" {
+		err = errors.New("empty")
+	}
+	if err != nil {
+		return "", fmt.Errorf("%w %q: %v", ErrInvalidKey, objectName, err)
+	}
+	return key, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| normalizePrefix: List prefixes follow the key rules but may be "" or end in "/"
+//||------------------------------------------------------------------------------------------------||
+
+func normalizePrefix(prefix string) (string, error) {
+	key, err := normalize(strings.TrimSuffix(prefix, "/"))
+	if err != nil {
+		return "", fmt.Errorf("%w: prefix %q: %v", ErrInvalidKey, prefix, err)
+	}
+	if key != "" && strings.HasSuffix(prefix, "/") {
+		key += "/"
+	}
+	return key, nil
+}
+
+func normalize(name string) (string, error) {
+	if !utf8.ValidString(name) {
+		return "", errors.New("not valid UTF-8")
+	}
+	key := norm.NFC.String(strings.TrimLeft(name, "/"))
+	if key == "" {
+		return "", nil
+	}
+	if len(key) > MaxKeyLength {
+		return "", fmt.Errorf("longer than %d bytes", MaxKeyLength)
+	}
+	for _, r := range key {
+		if r < 0x20 || r == 0x7f {
+			return "", errors.New("contains a control character")
+		}
+		if r == '\\' {
+			return "", errors.New("contains a backslash")
+		}
+	}
+	segments := strings.Split(key, "/")
+	for _, seg := range segments {
+		switch seg {
+		case "":
+			return "", errors.New("empty path segment")
+		case ".", "..":
+			return "", fmt.Errorf("%q path segment", seg)
+		}
+	}
+	for _, reserved := range reservedKeyRoots {
+		if segments[0] == reserved {
+			return "", fmt.Errorf("%s is reserved", reserved)
+		}
+	}
+	return key, nil
+}
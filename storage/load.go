@@ -1,6 +1,10 @@
 package storage
 
-import "github.com/ralphferrara/aria/config"
+import (
+	"time"
+
+	"github.com/ralphferrara/aria/config"
+)
 
 //||------------------------------------------------------------------------------------------------||
 //|| ConvertFromConfig: Helper to create StoreConfig from config.StorageInstanceConfig
@@ -17,5 +21,14 @@ func ConvertFromConfig(cfg config.StorageInstanceConfig) StoreConfig {
 		AccessKey:       cfg.AccessKey,
 		SecretKey:       cfg.SecretKey,
 		LocalPath:       cfg.Dir,
+		PublicURL:       cfg.PublicURL,
+		SignKey:         cfg.SignKey,
+		ProbeWrite:      cfg.ProbeWrite,
+		Timeout:         time.Duration(cfg.Timeout) * time.Second,
+		Retry: RetryPolicy{
+			MaxRetries: cfg.MaxRetries,
+			Backoff:    time.Duration(cfg.RetryBackoff) * time.Millisecond,
+			MaxBackoff: time.Duration(cfg.RetryMaxBackoff) * time.Millisecond,
+		},
 	}
 }
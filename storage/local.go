@@ -1,56 +1,100 @@
 package storage
 
 import (
-	"io/ioutil"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
 //||------------------------------------------------------------------------------------------------||
 //|| LocalBackend Struct
+//||
+//|| Every path goes through root (an os.Root), so no key, symlink or ".." can reach a file
+//|| outside the base directory. Writes land in .aria-tmp and are renamed into place.
 //||------------------------------------------------------------------------------------------------||
 
 type StorageEngineLocal struct {
 	basePath string
+	root     *os.Root
 	config   StoreConfig
+	mu       sync.RWMutex // held to rename an object and its sidecar; read-held to pair them up
 }
 
 //||------------------------------------------------------------------------------------------------||
 //|| NewLocalBackend Constructor
 //||------------------------------------------------------------------------------------------------||
 
-func NewLocalBackend(cfg StoreConfig) *StorageEngineLocal {
+func NewLocalBackend(cfg StoreConfig) (*StorageEngineLocal, error) {
 	path := cfg.LocalPath
-	_ = os.MkdirAll(path, 0770)
-	return &StorageEngineLocal{
+	if err := os.MkdirAll(path, 0770); err != nil {
+		return nil, fmt.Errorf("local storage: %w", err)
+	}
+	root, err := os.OpenRoot(path)
+	if err != nil {
+		return nil, fmt.Errorf("local storage: %w", err)
+	}
+	l := &StorageEngineLocal{
 		basePath: path,
+		root:     root,
 		config:   cfg,
 	}
+	l.sweepTemp()
+	return l, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Close: release the base directory
+//||------------------------------------------------------------------------------------------------||
+
+func (l *StorageEngineLocal) Close() error {
+	return l.root.Close()
 }
 
 //||------------------------------------------------------------------------------------------------||
 //|| Put: Write file
 //||------------------------------------------------------------------------------------------------||
 
-func (l *StorageEngineLocal) Put(objectName string, data []byte) error {
-	fullPath := filepath.Join(l.basePath, objectName)
-	dir := filepath.Dir(fullPath)
-	if err := os.MkdirAll(dir, 0770); err != nil {
+func (l *StorageEngineLocal) Put(ctx context.Context, objectName string, data []byte, opts ...PutOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	key, err := NormalizeKey(objectName)
+	if err != nil {
 		return err
 	}
-	return ioutil.WriteFile(fullPath, data, 0660)
+	return l.writeObject(key, putOptions(opts), func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
 }
 
 //||------------------------------------------------------------------------------------------------||
 //|| Get: Read file
 //||------------------------------------------------------------------------------------------------||
 
-func (l *StorageEngineLocal) Get(objectName string) ([]byte, error) {
-	fullPath := filepath.Join(l.basePath, objectName)
-	data, err := ioutil.ReadFile(fullPath)
+func (l *StorageEngineLocal) Get(ctx context.Context, objectName string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	key, err := NormalizeKey(objectName)
 	if err != nil {
 		return nil, err
 	}
+	data, err := l.root.ReadFile(filepath.FromSlash(key))
+	if err != nil {
+		return nil, notExist(key, err)
+	}
 	return data, nil
 }
 
@@ -58,7 +102,466 @@ func (l *StorageEngineLocal) Get(objectName string) ([]byte, error) {
 //|| Delete: Remove file
 //||------------------------------------------------------------------------------------------------||
 
-func (l *StorageEngineLocal) Delete(objectName string) error {
-	fullPath := filepath.Join(l.basePath, objectName)
-	return os.Remove(fullPath)
+func (l *StorageEngineLocal) Delete(ctx context.Context, objectName string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	key, err := NormalizeKey(objectName)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.root.Remove(filepath.FromSlash(key)); err != nil {
+		return notExist(key, err)
+	}
+	return l.removeMeta(key)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| PutStream: Copy a reader to file (a known size must match what was read)
+//||------------------------------------------------------------------------------------------------||
+
+func (l *StorageEngineLocal) PutStream(ctx context.Context, objectName string, r io.Reader, size int64, opts PutOptions) error {
+	key, err := NormalizeKey(objectName)
+	if err != nil {
+		return err
+	}
+	return l.writeObject(key, opts, func(w io.Writer) error {
+		n, err := io.Copy(w, ctxReader{ctx, r})
+		if err == nil && size >= 0 && n != size {
+			err = fmt.Errorf("short write: got %d bytes, want %d", n, size)
+		}
+		return err
+	})
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| GetStream: Open file
+//||------------------------------------------------------------------------------------------------||
+
+func (l *StorageEngineLocal) GetStream(ctx context.Context, objectName string) (io.ReadCloser, ObjectInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	key, err := NormalizeKey(objectName)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	f, st, meta, err := l.open(key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	info := objectInfo(key, st, meta)
+	info.Metadata = nil
+	return f, info, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| GetRange: Seek and limit
+//||------------------------------------------------------------------------------------------------||
+
+func (l *StorageEngineLocal) GetRange(ctx context.Context, objectName string, offset, length int64) (io.ReadCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := checkRange(offset, length); err != nil {
+		return nil, err
+	}
+	key, err := NormalizeKey(objectName)
+	if err != nil {
+		return nil, err
+	}
+	f, err := l.root.Open(filepath.FromSlash(key))
+	if err != nil {
+		return nil, notExist(key, err)
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if length == ToEnd {
+		return f, nil
+	}
+	return readCloser{io.LimitReader(f, length), f}, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Stat: file info plus the sidecar written by Put
+//||------------------------------------------------------------------------------------------------||
+
+func (l *StorageEngineLocal) Stat(ctx context.Context, objectName string) (ObjectInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return ObjectInfo{}, err
+	}
+	key, err := NormalizeKey(objectName)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	st, err := l.root.Stat(filepath.FromSlash(key))
+	if err == nil && st.IsDir() {
+		return ObjectInfo{}, notFound(key)
+	}
+	if err != nil {
+		return ObjectInfo{}, notExist(key, err)
+	}
+	return l.info(key, st), nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Exists
+//||------------------------------------------------------------------------------------------------||
+
+func (l *StorageEngineLocal) Exists(ctx context.Context, objectName string) (bool, error) {
+	return exists(l.Stat(ctx, objectName))
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| List: walk the tree, sort keys, page after cursor (the last key of the previous page)
+//||
+//|| Files whose names are not valid keys (written around the backend) are skipped.
+//||------------------------------------------------------------------------------------------------||
+
+func (l *StorageEngineLocal) List(ctx context.Context, prefix, cursor string) (ListPage, error) {
+	prefix, err := normalizePrefix(prefix)
+	if err != nil {
+		return ListPage{}, err
+	}
+	var keys []string
+	err = fs.WalkDir(l.root.FS(), ".", func(key string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if key == localMetaDir || key == localTempDir || (key != "." && !strings.HasPrefix(key+"/", prefix) && !strings.HasPrefix(prefix, key+"/")) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if strings.HasPrefix(key, prefix) && key > cursor {
+			if normalized, err := NormalizeKey(key); err == nil && normalized == key {
+				keys = append(keys, key)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return ListPage{}, err
+	}
+	sort.Strings(keys)
+
+	page := ListPage{}
+	if len(keys) > ListPageSize {
+		keys = keys[:ListPageSize]
+		page.NextCursor = keys[len(keys)-1]
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for _, key := range keys {
+		st, err := l.root.Stat(filepath.FromSlash(key))
+		if err != nil {
+			continue // removed since the walk
+		}
+		info := l.info(key, st)
+		info.Metadata = nil
+		page.Objects = append(page.Objects, info)
+	}
+	return page, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Copy: file and sidecar
+//||------------------------------------------------------------------------------------------------||
+
+func (l *StorageEngineLocal) Copy(ctx context.Context, src, dst string) error {
+	src, err := NormalizeKey(src)
+	if err != nil {
+		return err
+	}
+	dst, err = NormalizeKey(dst)
+	if err != nil {
+		return err
+	}
+	if src == dst {
+		_, err := l.Stat(ctx, src)
+		return err
+	}
+	f, st, meta, err := l.open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return l.PutStream(ctx, dst, f, st.Size(), meta)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Move: rename file and sidecar
+//||------------------------------------------------------------------------------------------------||
+
+func (l *StorageEngineLocal) Move(ctx context.Context, src, dst string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	src, err := NormalizeKey(src)
+	if err != nil {
+		return err
+	}
+	dst, err = NormalizeKey(dst)
+	if err != nil {
+		return err
+	}
+	dstPath := filepath.FromSlash(dst)
+	if err := l.root.MkdirAll(filepath.Dir(dstPath), 0770); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.root.Rename(filepath.FromSlash(src), dstPath); err != nil {
+		return notExist(src, err)
+	}
+	if _, err := l.root.Stat(l.metaPath(src)); errors.Is(err, fs.ErrNotExist) {
+		return l.removeMeta(dst)
+	}
+	if err := l.root.MkdirAll(filepath.Dir(l.metaPath(dst)), 0770); err != nil {
+		return err
+	}
+	return l.root.Rename(l.metaPath(src), l.metaPath(dst)) // the stamp moves with the data
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| writeObject: write data (and sidecar) to .aria-tmp, fsync, then rename both into place
+//||
+//|| The renames happen together under mu, data first. A crash between them leaves the new data
+//|| with the old sidecar, whose stamp no longer matches, so readMeta ignores it.
+//||------------------------------------------------------------------------------------------------||
+
+const (
+	localTempDir = ".aria-tmp"
+	localTempTTL = 24 * time.Hour // sweepTemp drops leftovers older than this
+)
+
+func (l *StorageEngineLocal) writeObject(key string, opts PutOptions, write func(w io.Writer) error) error {
+	name := filepath.FromSlash(key)
+	if err := l.root.MkdirAll(filepath.Dir(name), 0770); err != nil {
+		return err
+	}
+	tmp, st, err := l.writeTemp(write)
+	if err != nil {
+		return err
+	}
+	metaTmp := ""
+	if opts.ContentType != "" || opts.CacheControl != "" || len(opts.Metadata) > 0 {
+		metaTmp, err = l.writeMeta(opts, st)
+		if err != nil {
+			_ = l.root.Remove(tmp)
+			return err
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.root.Rename(tmp, name); err != nil {
+		_ = l.root.Remove(tmp)
+		if metaTmp != "" {
+			_ = l.root.Remove(metaTmp)
+		}
+		return err
+	}
+	if metaTmp == "" {
+		return l.removeMeta(key)
+	}
+	err = l.root.MkdirAll(filepath.Dir(l.metaPath(key)), 0770)
+	if err == nil {
+		err = l.root.Rename(metaTmp, l.metaPath(key))
+	}
+	if err != nil {
+		_ = l.root.Remove(metaTmp)
+	}
+	return err
+}
+
+// writeTemp writes a new file under .aria-tmp; the caller renames it into place.
+func (l *StorageEngineLocal) writeTemp(write func(w io.Writer) error) (string, fs.FileInfo, error) {
+	if err := l.root.MkdirAll(localTempDir, 0770); err != nil {
+		return "", nil, err
+	}
+	suffix := make([]byte, 8)
+	rand.Read(suffix)
+	tmp := filepath.Join(localTempDir, hex.EncodeToString(suffix))
+	f, err := l.root.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0660)
+	if err != nil {
+		return "", nil, err
+	}
+	err = write(f)
+	if err == nil {
+		err = f.Sync()
+	}
+	var st fs.FileInfo
+	if err == nil {
+		st, err = f.Stat()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = l.root.Remove(tmp)
+		return "", nil, err
+	}
+	return tmp, st, nil
+}
+
+// sweepTemp removes temp files left by a crash; recent ones may belong to another process's write.
+func (l *StorageEngineLocal) sweepTemp() {
+	entries, err := fs.ReadDir(l.root.FS(), localTempDir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if st, err := e.Info(); err == nil && time.Since(st.ModTime()) > localTempTTL {
+			_ = l.root.Remove(filepath.Join(localTempDir, e.Name()))
+		}
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Sidecar Metadata: <base>/.aria-meta/<object>.json, only when Put was given options
+//||
+//|| The sidecar is stamped with the size and mtime of the data file it was written with (a
+//|| rename keeps both), and a sidecar whose stamp does not match the file is ignored.
+//||------------------------------------------------------------------------------------------------||
+
+const localMetaDir = ".aria-meta"
+
+type localMeta struct {
+	PutOptions
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+func (l *StorageEngineLocal) metaPath(key string) string {
+	return filepath.Join(localMetaDir, filepath.FromSlash(key)+".json")
+}
+
+// writeMeta writes the sidecar for the data file st to .aria-tmp and returns its temp name.
+func (l *StorageEngineLocal) writeMeta(opts PutOptions, st fs.FileInfo) (string, error) {
+	data, err := json.Marshal(localMeta{PutOptions: opts, Size: st.Size(), ModTime: st.ModTime()})
+	if err != nil {
+		return "", err
+	}
+	tmp, _, err := l.writeTemp(func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+	return tmp, err
+}
+
+func (l *StorageEngineLocal) readMeta(key string, st fs.FileInfo) (PutOptions, error) {
+	var meta localMeta
+	data, err := l.root.ReadFile(l.metaPath(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return PutOptions{}, nil
+	}
+	if err != nil {
+		return PutOptions{}, err
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return PutOptions{}, err
+	}
+	if meta.Size != st.Size() || !meta.ModTime.Equal(st.ModTime()) {
+		return PutOptions{}, nil // left over from an interrupted write
+	}
+	return meta.PutOptions, nil
+}
+
+func (l *StorageEngineLocal) removeMeta(key string) error {
+	if err := l.root.Remove(l.metaPath(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// open opens key with its sidecar options, read-holding mu so the sidecar belongs to this file.
+func (l *StorageEngineLocal) open(key string) (*os.File, fs.FileInfo, PutOptions, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	f, err := l.root.Open(filepath.FromSlash(key))
+	if err != nil {
+		return nil, nil, PutOptions{}, notExist(key, err)
+	}
+	st, err := f.Stat()
+	if err == nil && st.IsDir() {
+		err = notFound(key)
+	}
+	if err != nil {
+		f.Close()
+		return nil, nil, PutOptions{}, err
+	}
+	meta, err := l.readMeta(key, st)
+	if err != nil {
+		f.Close()
+		return nil, nil, PutOptions{}, err
+	}
+	return f, st, meta, nil
+}
+
+// info builds ObjectInfo from a file and its sidecar.
+func (l *StorageEngineLocal) info(key string, st fs.FileInfo) ObjectInfo {
+	meta, _ := l.readMeta(key, st)
+	return objectInfo(key, st, meta)
+}
+
+// objectInfo: the content type falls back to the extension.
+func objectInfo(key string, st fs.FileInfo, meta PutOptions) ObjectInfo {
+	return ObjectInfo{
+		Name:         key,
+		Size:         st.Size(),
+		ContentType:  contentType(key, meta.ContentType),
+		CacheControl: meta.CacheControl,
+		LastModified: st.ModTime(),
+		Metadata:     meta.Metadata,
+	}
+}
+
+// notExist maps a missing file to ErrNotFound, as the cloud backends do.
+func notExist(key string, err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return notFound(key)
+	}
+	return err
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| ctxReader: stops a copy once ctx is done
+//||------------------------------------------------------------------------------------------------||
+
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c ctxReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Ping: Check the base directory exists (no writes)
+//||------------------------------------------------------------------------------------------------||
+
+func (l *StorageEngineLocal) Ping(ctx context.Context) error {
+	info, err := l.root.Stat(".")
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", l.basePath)
+	}
+	return nil
 }
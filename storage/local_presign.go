@@ -0,0 +1,170 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Local Presigned URLs
+//||
+//||   <public_url>/<key>?method=GET&expires=<unix>&ct=<type>&len=<bytes>&sig=<hex>
+//||
+//|| sig is HMAC-SHA256(sign_key, method \n key \n expires \n ct \n len). Handler verifies it and
+//|| serves the object (GET/HEAD, with ranges) or stores the body (PUT).
+//||------------------------------------------------------------------------------------------------||
+
+func (l *StorageEngineLocal) PresignGet(ctx context.Context, objectName string, ttl time.Duration) (string, error) {
+	return l.presign(http.MethodGet, objectName, ttl, PresignConstraints{})
+}
+
+func (l *StorageEngineLocal) PresignPut(ctx context.Context, objectName string, ttl time.Duration, c PresignConstraints) (string, error) {
+	return l.presign(http.MethodPut, objectName, ttl, c)
+}
+
+func (l *StorageEngineLocal) presign(method, objectName string, ttl time.Duration, c PresignConstraints) (string, error) {
+	if l.config.SignKey == "" || l.config.PublicURL == "" {
+		return "", fmt.Errorf("local presigned URLs need sign_key and public_url")
+	}
+	objectName, err := NormalizeKey(objectName)
+	if err != nil {
+		return "", err
+	}
+	q := url.Values{}
+	q.Set("method", method)
+	q.Set("expires", strconv.FormatInt(time.Now().Add(ttl).Unix(), 10))
+	if c.ContentType != "" {
+		q.Set("ct", c.ContentType)
+	}
+	if c.ContentLength > 0 {
+		q.Set("len", strconv.FormatInt(c.ContentLength, 10))
+	}
+	q.Set("sig", l.signature(objectName, q))
+
+	segments := strings.Split(objectName, "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	return strings.TrimRight(l.config.PublicURL, "/") + "/" + strings.Join(segments, "/") + "?" + q.Encode(), nil
+}
+
+func (l *StorageEngineLocal) signature(objectName string, q url.Values) string {
+	mac := hmac.New(sha256.New, []byte(l.config.SignKey))
+	mac.Write([]byte(strings.Join([]string{q.Get("method"), objectName, q.Get("expires"), q.Get("ct"), q.Get("len")}, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| verify: signature, expiry and method
+//||------------------------------------------------------------------------------------------------||
+
+func (l *StorageEngineLocal) verify(r *http.Request, objectName string) (int, error) {
+	q := r.URL.Query()
+	if l.config.SignKey == "" {
+		return http.StatusNotFound, fmt.Errorf("presigned URLs are disabled")
+	}
+	got, err := hex.DecodeString(q.Get("sig"))
+	if err != nil || !hmac.Equal(got, mustHex(l.signature(objectName, q))) {
+		return http.StatusForbidden, fmt.Errorf("invalid signature")
+	}
+	expires, err := strconv.ParseInt(q.Get("expires"), 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return http.StatusForbidden, fmt.Errorf("URL expired")
+	}
+	signed := q.Get("method")
+	if r.Method != signed && !(signed == http.MethodGet && r.Method == http.MethodHead) {
+		return http.StatusMethodNotAllowed, fmt.Errorf("URL is signed for %s", signed)
+	}
+	return http.StatusOK, nil
+}
+
+func mustHex(s string) []byte {
+	b, _ := hex.DecodeString(s)
+	return b
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Handler: mount under the path of public_url with http.StripPrefix
+//||------------------------------------------------------------------------------------------------||
+
+func (l *StorageEngineLocal) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		objectName, err := NormalizeKey(r.URL.Path)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if status, err := l.verify(r, objectName); err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+		if r.Method == http.MethodPut {
+			l.servePut(w, r, objectName)
+			return
+		}
+		l.serveGet(w, r, objectName)
+	})
+}
+
+func (l *StorageEngineLocal) serveGet(w http.ResponseWriter, r *http.Request, objectName string) {
+	rc, info, err := l.GetStream(r.Context(), objectName) // info carries the sidecar's headers
+	if errors.Is(err, ErrNotFound) {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "read failed", http.StatusInternalServerError)
+		return
+	}
+	defer rc.Close()
+	if info.ContentType == "" {
+		info.ContentType = contentType(objectName, "")
+	}
+	w.Header().Set("Content-Type", info.ContentType)
+	if info.CacheControl != "" {
+		w.Header().Set("Cache-Control", info.CacheControl)
+	}
+	if rs, ok := rc.(io.ReadSeeker); ok {
+		http.ServeContent(w, r, "", info.LastModified, rs) // Range, If-Modified-Since, HEAD
+		return
+	}
+	_, _ = io.Copy(w, rc)
+}
+
+func (l *StorageEngineLocal) servePut(w http.ResponseWriter, r *http.Request, objectName string) {
+	q := r.URL.Query()
+	ct := q.Get("ct")
+	if ct != "" && r.Header.Get("Content-Type") != ct {
+		http.Error(w, "Content-Type must be "+ct, http.StatusBadRequest)
+		return
+	}
+	size := int64(SizeUnknown)
+	if n := q.Get("len"); n != "" {
+		size, _ = strconv.ParseInt(n, 10, 64)
+		if r.ContentLength != size {
+			http.Error(w, "Content-Length must be "+n, http.StatusBadRequest)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, size)
+	}
+	err := l.PutStream(r.Context(), objectName, r.Body, size, PutOptions{ContentType: r.Header.Get("Content-Type")})
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		http.Error(w, "body too large", http.StatusRequestEntityTooLarge)
+	case err != nil:
+		http.Error(w, "upload failed", http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusOK)
+	}
+}
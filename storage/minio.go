@@ -5,6 +5,9 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
@@ -42,35 +45,41 @@ func NewMinioBackend(cfg StoreConfig) (*StorageEngineMinio, error) {
 //|| Put: Upload an object
 //||------------------------------------------------------------------------------------------------||
 
-func (m *StorageEngineMinio) Put(objectName string, data []byte) error {
-	ctx := context.Background()
+func (m *StorageEngineMinio) Put(ctx context.Context, objectName string, data []byte, opts ...PutOptions) error {
 	_, err := m.client.PutObject(
 		ctx,
 		m.config.Bucket,
 		objectName,
 		bytes.NewReader(data),
 		int64(len(data)),
-		minio.PutObjectOptions{},
+		m.putOptions(objectName, putOptions(opts)),
 	)
 	return err
 }
 
+func (m *StorageEngineMinio) putOptions(objectName string, opts PutOptions) minio.PutObjectOptions {
+	return minio.PutObjectOptions{
+		ContentType:  contentType(objectName, opts.ContentType),
+		CacheControl: opts.CacheControl,
+		UserMetadata: opts.Metadata,
+	}
+}
+
 //||------------------------------------------------------------------------------------------------||
 //|| Get: Download an object
 //||------------------------------------------------------------------------------------------------||
 
-func (m *StorageEngineMinio) Get(objectName string) ([]byte, error) {
-	ctx := context.Background()
+func (m *StorageEngineMinio) Get(ctx context.Context, objectName string) ([]byte, error) {
 	obj, err := m.client.GetObject(ctx, m.config.Bucket, objectName, minio.GetObjectOptions{})
 	if err != nil {
-		return nil, err
+		return nil, minioNotExist(objectName, err)
 	}
 	defer obj.Close()
 
 	buf := new(bytes.Buffer)
 	_, err = io.Copy(buf, obj)
 	if err != nil {
-		return nil, err
+		return nil, minioNotExist(objectName, err)
 	}
 	return buf.Bytes(), nil
 }
@@ -79,7 +88,205 @@ func (m *StorageEngineMinio) Get(objectName string) ([]byte, error) {
 //|| Delete: Delete an object
 //||------------------------------------------------------------------------------------------------||
 
-func (m *StorageEngineMinio) Delete(objectName string) error {
-	ctx := context.Background()
+func (m *StorageEngineMinio) Delete(ctx context.Context, objectName string) error {
 	return m.client.RemoveObject(ctx, m.config.Bucket, objectName, minio.RemoveObjectOptions{})
 }
+
+//||------------------------------------------------------------------------------------------------||
+//|| PutStream: Upload from a reader (size SizeUnknown streams as multipart)
+//||------------------------------------------------------------------------------------------------||
+
+func (m *StorageEngineMinio) PutStream(ctx context.Context, objectName string, r io.Reader, size int64, opts PutOptions) error {
+	_, err := m.client.PutObject(ctx, m.config.Bucket, objectName, r, size, m.putOptions(objectName, opts))
+	return err
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| GetStream: Download as a stream (Stat surfaces missing objects before the first Read)
+//||------------------------------------------------------------------------------------------------||
+
+func (m *StorageEngineMinio) GetStream(ctx context.Context, objectName string) (io.ReadCloser, ObjectInfo, error) {
+	obj, err := m.client.GetObject(ctx, m.config.Bucket, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, ObjectInfo{}, minioNotExist(objectName, err)
+	}
+	st, err := obj.Stat()
+	if err != nil {
+		obj.Close()
+		return nil, ObjectInfo{}, minioNotExist(objectName, err)
+	}
+	return obj, ObjectInfo{
+		Name:         objectName,
+		Size:         st.Size,
+		ContentType:  st.ContentType,
+		ETag:         st.ETag,
+		LastModified: st.LastModified,
+	}, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| GetRange: Download part of an object
+//||------------------------------------------------------------------------------------------------||
+
+func (m *StorageEngineMinio) GetRange(ctx context.Context, objectName string, offset, length int64) (io.ReadCloser, error) {
+	if err := checkRange(offset, length); err != nil {
+		return nil, err
+	}
+	opts := minio.GetObjectOptions{}
+	var err error
+	switch {
+	case length != ToEnd:
+		err = opts.SetRange(offset, offset+length-1)
+	case offset > 0:
+		err = opts.SetRange(offset, 0) // minio: end 0 = to the end
+	}
+	if err != nil {
+		return nil, err
+	}
+	obj, err := m.client.GetObject(ctx, m.config.Bucket, objectName, opts)
+	if err != nil {
+		return nil, minioNotExist(objectName, err)
+	}
+	if _, err := obj.Stat(); err != nil {
+		obj.Close()
+		return nil, minioNotExist(objectName, err)
+	}
+	return obj, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Stat: StatObject
+//||------------------------------------------------------------------------------------------------||
+
+func (m *StorageEngineMinio) Stat(ctx context.Context, objectName string) (ObjectInfo, error) {
+	st, err := m.client.StatObject(ctx, m.config.Bucket, objectName, minio.StatObjectOptions{})
+	if err != nil {
+		return ObjectInfo{}, minioNotExist(objectName, err)
+	}
+	return ObjectInfo{
+		Name:         objectName,
+		Size:         st.Size,
+		ContentType:  st.ContentType,
+		CacheControl: st.Metadata.Get("Cache-Control"),
+		ETag:         st.ETag,
+		LastModified: st.LastModified,
+		Metadata:     st.UserMetadata,
+	}, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Exists
+//||------------------------------------------------------------------------------------------------||
+
+func (m *StorageEngineMinio) Exists(ctx context.Context, objectName string) (bool, error) {
+	return exists(m.Stat(ctx, objectName))
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| List: recursive listing after cursor (the last key of the previous page)
+//||------------------------------------------------------------------------------------------------||
+
+func (m *StorageEngineMinio) List(ctx context.Context, prefix, cursor string) (ListPage, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // stops the listing goroutine once the page is full
+
+	page := ListPage{}
+	for o := range m.client.ListObjects(ctx, m.config.Bucket, minio.ListObjectsOptions{
+		Prefix:     prefix,
+		StartAfter: cursor,
+		Recursive:  true,
+		MaxKeys:    ListPageSize,
+	}) {
+		if o.Err != nil {
+			return ListPage{}, o.Err
+		}
+		if len(page.Objects) == ListPageSize {
+			page.NextCursor = page.Objects[len(page.Objects)-1].Name
+			break
+		}
+		page.Objects = append(page.Objects, ObjectInfo{
+			Name:         o.Key,
+			Size:         o.Size,
+			ContentType:  o.ContentType,
+			ETag:         o.ETag,
+			LastModified: o.LastModified,
+		})
+	}
+	return page, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Copy: server-side CopyObject
+//||------------------------------------------------------------------------------------------------||
+
+func (m *StorageEngineMinio) Copy(ctx context.Context, src, dst string) error {
+	_, err := m.client.CopyObject(ctx,
+		minio.CopyDestOptions{Bucket: m.config.Bucket, Object: dst},
+		minio.CopySrcOptions{Bucket: m.config.Bucket, Object: src},
+	)
+	return err
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Move: Copy then Delete
+//||------------------------------------------------------------------------------------------------||
+
+func (m *StorageEngineMinio) Move(ctx context.Context, src, dst string) error {
+	if err := m.Copy(ctx, src, dst); err != nil {
+		return err
+	}
+	return m.client.RemoveObject(ctx, m.config.Bucket, src, minio.RemoveObjectOptions{})
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| PresignGet / PresignPut: SigV4 query-signed URLs (constraints become signed headers)
+//||------------------------------------------------------------------------------------------------||
+
+func (m *StorageEngineMinio) PresignGet(ctx context.Context, objectName string, ttl time.Duration) (string, error) {
+	u, err := m.client.PresignedGetObject(ctx, m.config.Bucket, objectName, ttl, nil)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+func (m *StorageEngineMinio) PresignPut(ctx context.Context, objectName string, ttl time.Duration, c PresignConstraints) (string, error) {
+	headers := http.Header{}
+	if c.ContentType != "" {
+		headers.Set("Content-Type", c.ContentType)
+	}
+	if c.ContentLength > 0 {
+		headers.Set("Content-Length", strconv.FormatInt(c.ContentLength, 10))
+	}
+	u, err := m.client.PresignHeader(ctx, http.MethodPut, m.config.Bucket, objectName, ttl, nil, headers)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Ping: Check the bucket is reachable (no writes)
+//||------------------------------------------------------------------------------------------------||
+
+func (m *StorageEngineMinio) Ping(ctx context.Context) error {
+	ok, err := m.client.BucketExists(ctx, m.config.Bucket)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("bucket %q does not exist", m.config.Bucket)
+	}
+	return nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| minioNotExist: map NoSuchKey to ErrNotFound (GetObject reports it on the first Read/Stat)
+//||------------------------------------------------------------------------------------------------||
+
+func minioNotExist(objectName string, err error) error {
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return notFound(objectName)
+	}
+	return err
+}
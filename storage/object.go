@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"time"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| PutOptions: per-object settings for PutStream
+//||------------------------------------------------------------------------------------------------||
+
+type PutOptions struct {
+	ContentType  string            `json:"content_type,omitempty"`  // "" = guessed from the object name's extension
+	CacheControl string            `json:"cache_control,omitempty"` // e.g. "public, max-age=31536000"
+	Metadata     map[string]string `json:"metadata,omitempty"`      // user metadata; backends may change key case
+}
+
+// putOptions returns the first of Put's optional options, or the zero value.
+func putOptions(opts []PutOptions) PutOptions {
+	if len(opts) > 0 {
+		return opts[0]
+	}
+	return PutOptions{}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| ObjectInfo: what a backend reports about a stored object
+//||------------------------------------------------------------------------------------------------||
+
+type ObjectInfo struct {
+	Name         string
+	Size         int64
+	ContentType  string
+	CacheControl string
+	ETag         string
+	LastModified time.Time
+	Metadata     map[string]string // Stat only; List and GetStream may leave it empty
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| ListPage: one page of List; NextCursor is "" on the last page
+//||------------------------------------------------------------------------------------------------||
+
+type ListPage struct {
+	Objects    []ObjectInfo
+	NextCursor string
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| ErrNotFound: Stat (and friends) wrap it for missing objects; test with errors.Is
+//||------------------------------------------------------------------------------------------------||
+
+var ErrNotFound = errors.New("object not found")
+
+func notFound(objectName string) error {
+	return fmt.Errorf("%s: %w", objectName, ErrNotFound)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Size / Length Conventions
+//||------------------------------------------------------------------------------------------------||
+
+const (
+	SizeUnknown  = -1   // PutStream: read until EOF (backends switch to chunked/multipart uploads)
+	ToEnd        = -1   // GetRange: read from offset to the end of the object
+	ListPageSize = 1000 // List: objects per page
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| contentType: explicit value, else by extension, else application/octet-stream
+//||------------------------------------------------------------------------------------------------||
+
+func contentType(objectName, explicit string) string {
+	if explicit != "" {
+		return explicit
+	}
+	if ct := mime.TypeByExtension(path.Ext(objectName)); ct != "" {
+		return ct
+	}
+	return "application/octet-stream"
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| checkRange: offset >= 0; length > 0 or ToEnd
+//||------------------------------------------------------------------------------------------------||
+
+func checkRange(offset, length int64) error {
+	if offset < 0 {
+		return fmt.Errorf("invalid range: negative offset %d", offset)
+	}
+	if length <= 0 && length != ToEnd {
+		return fmt.Errorf("invalid range: length %d (want > 0 or ToEnd)", length)
+	}
+	return nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| httpRange: Range header value for S3-compatible APIs
+//||------------------------------------------------------------------------------------------------||
+
+func httpRange(offset, length int64) string {
+	if length == ToEnd {
+		return fmt.Sprintf("bytes=%d-", offset)
+	}
+	return fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| readCloser: pairs a limited reader with the Close of what it wraps
+//||------------------------------------------------------------------------------------------------||
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| exists: Exists in terms of Stat
+//||------------------------------------------------------------------------------------------------||
+
+func exists(info ObjectInfo, err error) (bool, error) {
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
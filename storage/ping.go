@@ -1,29 +1,58 @@
 package storage
 
 import (
+	"context"
 	"fmt"
 	"time"
 )
 
+//||------------------------------------------------------------------------------------------------||
+//|| Pinger: backends that can check reachability without writing
+//||------------------------------------------------------------------------------------------------||
+
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
 //||------------------------------------------------------------------------------------------------||
 //|| Ping (generic for any backend)
 //||------------------------------------------------------------------------------------------------||
 
 func (s *Storage) Ping() error {
+	return s.PingContext(context.Background())
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| PingContext: bucket/container check; the write probe only runs when ProbeWrite is set
+//||------------------------------------------------------------------------------------------------||
+
+func (s *Storage) PingContext(ctx context.Context) error {
 	if s == nil || s.service == nil {
 		return fmt.Errorf("storage service not initialized")
 	}
+	if s.Config.ProbeWrite {
+		return s.probeWrite(ctx)
+	}
+	if p, ok := s.service.(Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| probeWrite: write/read/delete a dummy object
+//||------------------------------------------------------------------------------------------------||
 
-	// Lightweight test: try to write/read/delete a dummy object
+func (s *Storage) probeWrite(ctx context.Context) error {
 	testKey := fmt.Sprintf("healthcheck-%d", time.Now().UnixNano())
 	testData := []byte("ok")
 
-	if err := s.service.Put(testKey, testData); err != nil {
+	if err := s.Put(ctx, testKey, testData); err != nil {
 		return fmt.Errorf("put failed: %w", err)
 	}
-	defer s.service.Delete(testKey)
+	defer s.Delete(context.WithoutCancel(ctx), testKey)
 
-	data, err := s.service.Get(testKey)
+	data, err := s.Get(ctx, testKey)
 	if err != nil {
 		return fmt.Errorf("get failed: %w", err)
 	}
@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Presigner: backends that can mint time-limited URLs for direct client access
+//||------------------------------------------------------------------------------------------------||
+
+type Presigner interface {
+	PresignGet(ctx context.Context, objectName string, ttl time.Duration) (string, error)
+	PresignPut(ctx context.Context, objectName string, ttl time.Duration, c PresignConstraints) (string, error)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| PresignConstraints: what an upload URL binds the client to
+//||
+//|| Signed into the URL, so the client must send matching headers. Azure SAS cannot bind
+//|| headers, so the Azure backend rejects non-empty constraints rather than ignoring them.
+//||------------------------------------------------------------------------------------------------||
+
+type PresignConstraints struct {
+	ContentType   string // exact Content-Type the upload must send ("" = any)
+	ContentLength int64  // exact body size in bytes (0 = any)
+}
+
+func (c PresignConstraints) empty() bool {
+	return c.ContentType == "" && c.ContentLength == 0
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Limits
+//||------------------------------------------------------------------------------------------------||
+
+const MaxPresignTTL = 7 * 24 * time.Hour // S3/GCS V4 signatures cannot live longer
+
+func checkTTL(ttl time.Duration) error {
+	if ttl <= 0 || ttl > MaxPresignTTL {
+		return fmt.Errorf("presign ttl %s out of range (0, %s]", ttl, MaxPresignTTL)
+	}
+	return nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| PresignGet: download URL valid for ttl
+//||------------------------------------------------------------------------------------------------||
+
+func (s *Storage) PresignGet(ctx context.Context, objectName string, ttl time.Duration) (string, error) {
+	p, err := s.presigner()
+	if err != nil {
+		return "", err
+	}
+	objectName, err = NormalizeKey(objectName)
+	if err != nil {
+		return "", err
+	}
+	if err := checkTTL(ttl); err != nil {
+		return "", err
+	}
+	return p.PresignGet(ctx, objectName, ttl)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| PresignPut: upload URL valid for ttl (the client sends a PUT with the object as the body)
+//||------------------------------------------------------------------------------------------------||
+
+func (s *Storage) PresignPut(ctx context.Context, objectName string, ttl time.Duration, c PresignConstraints) (string, error) {
+	p, err := s.presigner()
+	if err != nil {
+		return "", err
+	}
+	objectName, err = NormalizeKey(objectName)
+	if err != nil {
+		return "", err
+	}
+	if err := checkTTL(ttl); err != nil {
+		return "", err
+	}
+	if c.ContentLength < 0 {
+		return "", fmt.Errorf("presign: negative content length %d", c.ContentLength)
+	}
+	return p.PresignPut(ctx, objectName, ttl, c)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Handler: serves presigned URLs for backends that have no public endpoint (local)
+//||
+//|| Mount it at the path of the instance's public_url (app.MountStorage does this), e.g.
+//||   h, _ := st.Handler(); httpWrapper.HandlePrefix("/files", http.StripPrefix("/files", h))
+//||------------------------------------------------------------------------------------------------||
+
+func (s *Storage) Handler() (http.Handler, error) {
+	if s == nil || s.service == nil {
+		return nil, fmt.Errorf("storage backend not initialized")
+	}
+	h, ok := s.service.(interface{ Handler() http.Handler })
+	if !ok {
+		return nil, fmt.Errorf("storage backend %s serves presigned URLs itself", s.Config.Backend)
+	}
+	return h.Handler(), nil
+}
+
+func (s *Storage) presigner() (Presigner, error) {
+	if s == nil || s.service == nil {
+		return nil, fmt.Errorf("storage backend not initialized")
+	}
+	p, ok := s.service.(Presigner)
+	if !ok {
+		return nil, fmt.Errorf("storage backend %s does not support presigned URLs", s.Config.Backend)
+	}
+	return p, nil
+}
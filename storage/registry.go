@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ralphferrara/aria/config"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Factory: builds a StoreService from a converted StoreConfig
+//||------------------------------------------------------------------------------------------------||
+
+type Factory func(cfg StoreConfig) (StoreService, error)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Registry
+//||------------------------------------------------------------------------------------------------||
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{}
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Register: add (or replace) a backend; names are case-insensitive
+//||------------------------------------------------------------------------------------------------||
+
+func Register(backend string, factory Factory) {
+	if backend == "" || factory == nil {
+		panic("storage: Register requires a backend name and factory")
+	}
+	registryMu.Lock()
+	registry[strings.ToLower(backend)] = factory
+	registryMu.Unlock()
+	config.RegisterBackend(config.SectionStorage, backend)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Backends: registered backend names (sorted)
+//||------------------------------------------------------------------------------------------------||
+
+func Backends() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	out := make([]string, 0, len(registry))
+	for name := range registry {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| lookup
+//||------------------------------------------------------------------------------------------------||
+
+func lookup(backend StoreBackend) (Factory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	f, ok := registry[strings.ToLower(string(backend))]
+	return f, ok
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Built-in Backends
+//||------------------------------------------------------------------------------------------------||
+
+func init() {
+	Register(string(StorageS3), func(cfg StoreConfig) (StoreService, error) {
+		svc, err := NewS3Backend(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("S3 backend init failed: %w", err)
+		}
+		return svc, nil
+	})
+	Register(string(StorageMinIO), func(cfg StoreConfig) (StoreService, error) {
+		svc, err := NewMinioBackend(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("MinIO backend init failed: %w", err)
+		}
+		return svc, nil
+	})
+	Register(string(StorageAzure), func(cfg StoreConfig) (StoreService, error) {
+		svc, err := NewAzureBackend(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("Azure backend init failed: %w", err)
+		}
+		return svc, nil
+	})
+	gcp := func(cfg StoreConfig) (StoreService, error) {
+		svc, err := NewGCPBackend(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("GCP backend init failed: %w", err)
+		}
+		return svc, nil
+	}
+	Register(string(StorageGCP), gcp)
+	Register("gcs", gcp)
+	Register(string(StorageLocal), func(cfg StoreConfig) (StoreService, error) {
+		return NewLocalBackend(cfg)
+	})
+}
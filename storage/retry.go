@@ -0,0 +1,278 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/minio/minio-go/v7"
+	"google.golang.org/api/googleapi"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| Defaults
+//||------------------------------------------------------------------------------------------------||
+
+const (
+	DefaultTimeout         = 60 * time.Second
+	DefaultRetryBackoff    = 100 * time.Millisecond
+	DefaultRetryMaxBackoff = 5 * time.Second
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| RetryPolicy: exponential backoff with jitter on retryable errors (0 MaxRetries = off)
+//||------------------------------------------------------------------------------------------------||
+
+type RetryPolicy struct {
+	MaxRetries int           `json:"max_retries,omitempty"`
+	Backoff    time.Duration `json:"backoff,omitempty"`     // before the first retry, doubled each time
+	MaxBackoff time.Duration `json:"max_backoff,omitempty"` // cap per wait
+}
+
+// delay is the wait before retry n (0-based): half the backoff plus up to half again at random.
+func (p RetryPolicy) delay(n int) time.Duration {
+	d, limit := p.Backoff, p.MaxBackoff
+	if d <= 0 {
+		d = DefaultRetryBackoff
+	}
+	if limit <= 0 {
+		limit = DefaultRetryMaxBackoff
+	}
+	for i := 0; i < n && d < limit; i++ {
+		d *= 2
+	}
+	if d > limit {
+		d = limit
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| do: run op under the instance timeout (when ctx has no deadline) with the retry policy
+//||------------------------------------------------------------------------------------------------||
+
+func (s *Storage) do(ctx context.Context, op func(ctx context.Context) error) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	return s.retry(ctx, op)
+}
+
+func (s *Storage) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if d := s.timeout(ctx); d > 0 {
+		return context.WithTimeout(ctx, d)
+	}
+	return context.WithCancel(ctx)
+}
+
+// timeout is the per-call limit, or 0 when ctx already carries a deadline of its own.
+func (s *Storage) timeout(ctx context.Context) time.Duration {
+	if _, ok := ctx.Deadline(); ok {
+		return 0
+	}
+	if s.Config.Timeout > 0 {
+		return s.Config.Timeout
+	}
+	return DefaultTimeout
+}
+
+func (s *Storage) retry(ctx context.Context, op func(ctx context.Context) error) error {
+	p := s.Config.Retry
+	for attempt := 0; ; attempt++ {
+		err := op(ctx)
+		if err == nil || attempt >= p.MaxRetries || !retryable(err) || ctx.Err() != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(p.delay(attempt)):
+		}
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| openStream: bound the time to open a stream by the timeout, not the time spent reading it
+//||------------------------------------------------------------------------------------------------||
+
+func (s *Storage) openStream(ctx context.Context, open func(ctx context.Context) (io.ReadCloser, error)) (io.ReadCloser, error) {
+	ctx, idle := s.withIdleTimeout(ctx)
+	var rc io.ReadCloser
+	err := s.retry(ctx, func(ctx context.Context) error {
+		var err error
+		rc, err = open(ctx)
+		return err
+	})
+	if err == nil && !idle.pause() {
+		rc.Close()
+		err = ErrStreamTimeout // fired just as the stream opened
+	}
+	if err != nil {
+		err = idle.err(err)
+		idle.stop()
+		return nil, err
+	}
+	return readCloser{rc, closeFunc(func() error { defer idle.stop(); return rc.Close() })}, nil
+}
+
+type closeFunc func() error
+
+func (f closeFunc) Close() error { return f() }
+
+//||------------------------------------------------------------------------------------------------||
+//|| idleTimer: cancel a streaming call once it has gone the instance timeout without progress
+//||------------------------------------------------------------------------------------------------||
+
+var ErrStreamTimeout = fmt.Errorf("storage stream made no progress within the timeout: %w", context.DeadlineExceeded)
+
+type idleTimer struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	d      time.Duration
+	mu     sync.Mutex
+	timer  *time.Timer // nil when ctx carries its own deadline
+}
+
+func (s *Storage) withIdleTimeout(ctx context.Context) (context.Context, *idleTimer) {
+	d := s.timeout(ctx)
+	ctx, cancel := context.WithCancelCause(ctx)
+	t := &idleTimer{ctx: ctx, cancel: cancel, d: d}
+	if d > 0 {
+		t.timer = time.AfterFunc(d, func() { cancel(ErrStreamTimeout) })
+	}
+	return ctx, t
+}
+
+// touch restarts the countdown after progress.
+func (t *idleTimer) touch() {
+	if t.timer != nil {
+		t.mu.Lock()
+		t.timer.Reset(t.d)
+		t.mu.Unlock()
+	}
+}
+
+// pause stops the countdown; false when it had already fired.
+func (t *idleTimer) pause() bool {
+	if t.timer == nil {
+		return true
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.timer.Stop()
+}
+
+func (t *idleTimer) stop() {
+	t.pause()
+	t.cancel(context.Canceled)
+}
+
+// err reports ErrStreamTimeout in place of the cancellation the timer caused.
+func (t *idleTimer) err(err error) error {
+	if err != nil && errors.Is(context.Cause(t.ctx), ErrStreamTimeout) {
+		return ErrStreamTimeout
+	}
+	return err
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| watchProgress: r with every read restarting idle; keeps io.Seeker/io.ReaderAt for the SDKs
+//||------------------------------------------------------------------------------------------------||
+
+func watchProgress(r io.Reader, idle *idleTimer) io.Reader {
+	read := progressReader{r, idle}
+	seeker, ok := r.(io.Seeker)
+	if !ok {
+		return read
+	}
+	if at, ok := r.(io.ReaderAt); ok {
+		return struct {
+			progressReader
+			io.Seeker
+			progressReaderAt
+		}{read, seeker, progressReaderAt{at, idle}}
+	}
+	return struct {
+		progressReader
+		io.Seeker
+	}{read, seeker}
+}
+
+type progressReader struct {
+	r    io.Reader
+	idle *idleTimer
+}
+
+func (p progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.idle.touch()
+	}
+	return n, err
+}
+
+type progressReaderAt struct {
+	r    io.ReaderAt
+	idle *idleTimer
+}
+
+func (p progressReaderAt) ReadAt(b []byte, off int64) (int, error) {
+	n, err := p.r.ReadAt(b, off)
+	if n > 0 {
+		p.idle.touch()
+	}
+	return n, err
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| retryable: transient network failures, throttling and 5xx from any backend SDK
+//||------------------------------------------------------------------------------------------------||
+
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrNotFound) {
+		return false
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return retryableStatus(statusCode(err))
+}
+
+func statusCode(err error) int {
+	var withStatus interface{ HTTPStatusCode() int } // S3 (smithy response errors)
+	if errors.As(err, &withStatus) {
+		return withStatus.HTTPStatusCode()
+	}
+	var azErr *azcore.ResponseError
+	if errors.As(err, &azErr) {
+		return azErr.StatusCode
+	}
+	var gErr *googleapi.Error
+	if errors.As(err, &gErr) {
+		return gErr.Code
+	}
+	if resp := minio.ToErrorResponse(err); resp.StatusCode != 0 {
+		return resp.StatusCode
+	}
+	return 0
+}
+
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusInternalServerError,
+		http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
@@ -3,14 +3,19 @@ package storage
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
 //||------------------------------------------------------------------------------------------------||
@@ -18,8 +23,9 @@ import (
 //||------------------------------------------------------------------------------------------------||
 
 type StorageEngineS3 struct {
-	client *s3.Client
-	config StoreConfig
+	client   *s3.Client
+	uploader *manager.Uploader // multipart for large or unknown-size streams
+	config   StoreConfig
 }
 
 //||------------------------------------------------------------------------------------------------||
@@ -48,8 +54,9 @@ func NewS3Backend(cfg StoreConfig) (*StorageEngineS3, error) {
 	}
 	client := s3.NewFromConfig(awsCfg)
 	return &StorageEngineS3{
-		client: client,
-		config: cfg,
+		client:   client,
+		uploader: manager.NewUploader(client),
+		config:   cfg,
 	}, nil
 }
 
@@ -57,28 +64,36 @@ func NewS3Backend(cfg StoreConfig) (*StorageEngineS3, error) {
 //|| Put: Upload an object
 //||------------------------------------------------------------------------------------------------||
 
-func (s *StorageEngineS3) Put(objectName string, data []byte) error {
-	ctx := context.Background()
-	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket: aws.String(s.config.Bucket),
-		Key:    aws.String(objectName),
-		Body:   bytes.NewReader(data),
-	})
+func (s *StorageEngineS3) Put(ctx context.Context, objectName string, data []byte, opts ...PutOptions) error {
+	_, err := s.client.PutObject(ctx, s.putInput(objectName, bytes.NewReader(data), putOptions(opts)))
 	return err
 }
 
+func (s *StorageEngineS3) putInput(objectName string, body io.Reader, opts PutOptions) *s3.PutObjectInput {
+	in := &s3.PutObjectInput{
+		Bucket:      aws.String(s.config.Bucket),
+		Key:         aws.String(objectName),
+		Body:        body,
+		ContentType: aws.String(contentType(objectName, opts.ContentType)),
+		Metadata:    opts.Metadata,
+	}
+	if opts.CacheControl != "" {
+		in.CacheControl = aws.String(opts.CacheControl)
+	}
+	return in
+}
+
 //||------------------------------------------------------------------------------------------------||
 //|| Get: Download an object
 //||------------------------------------------------------------------------------------------------||
 
-func (s *StorageEngineS3) Get(objectName string) ([]byte, error) {
-	ctx := context.Background()
+func (s *StorageEngineS3) Get(ctx context.Context, objectName string) ([]byte, error) {
 	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(s.config.Bucket),
 		Key:    aws.String(objectName),
 	})
 	if err != nil {
-		return nil, err
+		return nil, s3NotExist(objectName, err)
 	}
 	defer out.Body.Close()
 	return io.ReadAll(out.Body)
@@ -88,11 +103,209 @@ func (s *StorageEngineS3) Get(objectName string) ([]byte, error) {
 //|| Delete: Delete an object
 //||------------------------------------------------------------------------------------------------||
 
-func (s *StorageEngineS3) Delete(objectName string) error {
-	ctx := context.Background()
+func (s *StorageEngineS3) Delete(ctx context.Context, objectName string) error {
 	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(s.config.Bucket),
 		Key:    aws.String(objectName),
 	})
 	return err
 }
+
+//||------------------------------------------------------------------------------------------------||
+//|| PutStream: Upload from a reader (multipart above the part size or when size is unknown)
+//||------------------------------------------------------------------------------------------------||
+
+func (s *StorageEngineS3) PutStream(ctx context.Context, objectName string, r io.Reader, size int64, opts PutOptions) error {
+	in := s.putInput(objectName, r, opts)
+	if size >= 0 {
+		in.ContentLength = aws.Int64(size)
+	}
+	_, err := s.uploader.Upload(ctx, in)
+	return err
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| GetStream: Download as a stream
+//||------------------------------------------------------------------------------------------------||
+
+func (s *StorageEngineS3) GetStream(ctx context.Context, objectName string) (io.ReadCloser, ObjectInfo, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(objectName),
+	})
+	if err != nil {
+		return nil, ObjectInfo{}, s3NotExist(objectName, err)
+	}
+	return out.Body, ObjectInfo{
+		Name:         objectName,
+		Size:         aws.ToInt64(out.ContentLength),
+		ContentType:  aws.ToString(out.ContentType),
+		ETag:         strings.Trim(aws.ToString(out.ETag), `"`),
+		LastModified: aws.ToTime(out.LastModified),
+	}, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| GetRange: Download part of an object
+//||------------------------------------------------------------------------------------------------||
+
+func (s *StorageEngineS3) GetRange(ctx context.Context, objectName string, offset, length int64) (io.ReadCloser, error) {
+	if err := checkRange(offset, length); err != nil {
+		return nil, err
+	}
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(objectName),
+		Range:  aws.String(httpRange(offset, length)),
+	})
+	if err != nil {
+		return nil, s3NotExist(objectName, err)
+	}
+	return out.Body, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Stat: HeadObject
+//||------------------------------------------------------------------------------------------------||
+
+func (s *StorageEngineS3) Stat(ctx context.Context, objectName string) (ObjectInfo, error) {
+	out, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(objectName),
+	})
+	if err != nil {
+		return ObjectInfo{}, s3NotExist(objectName, err)
+	}
+	return ObjectInfo{
+		Name:         objectName,
+		Size:         aws.ToInt64(out.ContentLength),
+		ContentType:  aws.ToString(out.ContentType),
+		CacheControl: aws.ToString(out.CacheControl),
+		ETag:         strings.Trim(aws.ToString(out.ETag), `"`),
+		LastModified: aws.ToTime(out.LastModified),
+		Metadata:     out.Metadata,
+	}, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Exists
+//||------------------------------------------------------------------------------------------------||
+
+func (s *StorageEngineS3) Exists(ctx context.Context, objectName string) (bool, error) {
+	return exists(s.Stat(ctx, objectName))
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| List: ListObjectsV2 (cursor is the continuation token)
+//||------------------------------------------------------------------------------------------------||
+
+func (s *StorageEngineS3) List(ctx context.Context, prefix, cursor string) (ListPage, error) {
+	in := &s3.ListObjectsV2Input{
+		Bucket:  aws.String(s.config.Bucket),
+		Prefix:  aws.String(prefix),
+		MaxKeys: aws.Int32(ListPageSize),
+	}
+	if cursor != "" {
+		in.ContinuationToken = aws.String(cursor)
+	}
+	out, err := s.client.ListObjectsV2(ctx, in)
+	if err != nil {
+		return ListPage{}, err
+	}
+	page := ListPage{Objects: make([]ObjectInfo, 0, len(out.Contents))}
+	for _, o := range out.Contents {
+		page.Objects = append(page.Objects, ObjectInfo{
+			Name:         aws.ToString(o.Key),
+			Size:         aws.ToInt64(o.Size),
+			ETag:         strings.Trim(aws.ToString(o.ETag), `"`),
+			LastModified: aws.ToTime(o.LastModified),
+		})
+	}
+	if aws.ToBool(out.IsTruncated) {
+		page.NextCursor = aws.ToString(out.NextContinuationToken)
+	}
+	return page, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Copy: server-side CopyObject (metadata is copied with the object)
+//||------------------------------------------------------------------------------------------------||
+
+func (s *StorageEngineS3) Copy(ctx context.Context, src, dst string) error {
+	_, err := s.client.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String(s.config.Bucket),
+		Key:        aws.String(dst),
+		CopySource: aws.String(s.config.Bucket + "/" + url.PathEscape(src)),
+	})
+	return err
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Move: Copy then Delete
+//||------------------------------------------------------------------------------------------------||
+
+func (s *StorageEngineS3) Move(ctx context.Context, src, dst string) error {
+	if err := s.Copy(ctx, src, dst); err != nil {
+		return err
+	}
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(src),
+	})
+	return err
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| PresignGet / PresignPut: SigV4 query-signed URLs (Content-Type/Length are signed headers)
+//||------------------------------------------------------------------------------------------------||
+
+func (s *StorageEngineS3) PresignGet(ctx context.Context, objectName string, ttl time.Duration) (string, error) {
+	req, err := s3.NewPresignClient(s.client).PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(objectName),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", err
+	}
+	return req.URL, nil
+}
+
+func (s *StorageEngineS3) PresignPut(ctx context.Context, objectName string, ttl time.Duration, c PresignConstraints) (string, error) {
+	in := &s3.PutObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(objectName),
+	}
+	if c.ContentType != "" {
+		in.ContentType = aws.String(c.ContentType)
+	}
+	if c.ContentLength > 0 {
+		in.ContentLength = aws.Int64(c.ContentLength)
+	}
+	req, err := s3.NewPresignClient(s.client).PresignPutObject(ctx, in, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", err
+	}
+	return req.URL, nil
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Ping: Check the bucket is reachable (no writes)
+//||------------------------------------------------------------------------------------------------||
+
+func (s *StorageEngineS3) Ping(ctx context.Context) error {
+	_, err := s.client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(s.config.Bucket)})
+	return err
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| s3NotExist: map NoSuchKey (GetObject) and NotFound (HeadObject) to ErrNotFound
+//||------------------------------------------------------------------------------------------------||
+
+func s3NotExist(objectName string, err error) error {
+	var nsk *types.NoSuchKey
+	var nf *types.NotFound
+	if errors.As(err, &nsk) || errors.As(err, &nf) {
+		return notFound(objectName)
+	}
+	return err
+}
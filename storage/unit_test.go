@@ -0,0 +1,872 @@
+//||------------------------------------------------------------------------------------------------||
+//|| Storage Package: Unit Tests
+//|| unit_test.go
+//||------------------------------------------------------------------------------------------------||
+
+package storage
+
+//||------------------------------------------------------------------------------------------------||
+//|| Import
+//||------------------------------------------------------------------------------------------------||
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	gcs "cloud.google.com/go/storage"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/minio/minio-go/v7"
+	miniocreds "github.com/minio/minio-go/v7/pkg/credentials"
+	"google.golang.org/api/option"
+)
+
+//||------------------------------------------------------------------------------------------------||
+//|| helper: local storage under a temp dir
+//||------------------------------------------------------------------------------------------------||
+
+func newLocal(t *testing.T) (*Storage, string) {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "root")
+	st := &Storage{Config: StoreConfig{Backend: StorageLocal, LocalPath: dir}}
+	if err := st.InitStorage(); err != nil {
+		t.Fatalf("InitStorage: %v", err)
+	}
+	return st, dir
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test NormalizeKey
+//||------------------------------------------------------------------------------------------------||
+
+func TestNormalizeKey(t *testing.T) {
+	good := map[string]string{
+		"a.txt":         "a.txt",
+		"/img/a.png":    "img/a.png",
+		"cafe\u0301.md": "caf\u00e9.md", // NFC
+		"a/.hidden/b":   "a/.hidden/b",
+	}
+	for in, want := range good {
+		if got, err := NormalizeKey(in); err != nil || got != want {
+			t.Errorf("NormalizeKey(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	bad := []string{
+		"", "/", "../../etc/passwd", "a/../../b", "a/./b", "a//b", "a/", `a\..\b`,
+		"a\x00b", "\xff", ".aria-meta/x.json", ".aria-tmp/x", strings.Repeat("k", MaxKeyLength+1),
+	}
+	for _, in := range bad {
+		if got, err := NormalizeKey(in); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("NormalizeKey(%q) = %q, %v; want ErrInvalidKey", in, got, err)
+		}
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Local: keys cannot leave the root, through ".." or a symlink
+//||------------------------------------------------------------------------------------------------||
+
+func TestLocal_Confined(t *testing.T) {
+	st, dir := newLocal(t)
+	ctx := context.Background()
+	outside := filepath.Join(filepath.Dir(dir), "secret")
+	if err := os.WriteFile(outside, []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := st.Put(ctx, "../secret", []byte("x")); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("Put(../secret) = %v, want ErrInvalidKey", err)
+	}
+	if _, err := st.service.Get(ctx, "../secret"); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("engine Get(../secret) = %v, want ErrInvalidKey", err)
+	}
+	if err := os.Symlink(outside, filepath.Join(dir, "link")); err != nil {
+		t.Skipf("symlink: %v", err)
+	}
+	if data, err := st.Get(ctx, "link"); err == nil {
+		t.Fatalf("Get(link) read %q from outside the root", data)
+	}
+	if err := st.Put(ctx, "link", []byte("overwritten")); err != nil {
+		t.Fatalf("Put(link): %v", err)
+	}
+	if data, _ := os.ReadFile(outside); string(data) != "secret" {
+		t.Fatalf("file outside the root changed to %q", data)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Local: a failed write leaves the previous object intact and no temp files behind
+//||------------------------------------------------------------------------------------------------||
+
+func TestLocal_AtomicWrite(t *testing.T) {
+	st, dir := newLocal(t)
+	ctx := context.Background()
+	if err := st.Put(ctx, "docs/a.txt", []byte("v1")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	err := st.PutStream(ctx, "docs/a.txt", io.MultiReader(strings.NewReader("partial"), errReader{}), SizeUnknown, PutOptions{})
+	if err == nil {
+		t.Fatal("PutStream with a failing reader succeeded")
+	}
+	if data, err := st.Get(ctx, "docs/a.txt"); err != nil || string(data) != "v1" {
+		t.Fatalf("after failed write Get = %q, %v; want v1", data, err)
+	}
+	if tmp, _ := os.ReadDir(filepath.Join(dir, localTempDir)); len(tmp) != 0 {
+		t.Fatalf("%d temp files left behind", len(tmp))
+	}
+
+	page, err := st.List(ctx, "", "")
+	if err != nil || len(page.Objects) != 1 || page.Objects[0].Name != "docs/a.txt" {
+		t.Fatalf("List = %+v, %v; want only docs/a.txt", page.Objects, err)
+	}
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) { return 0, errors.New("connection lost") }
+
+//||------------------------------------------------------------------------------------------------||
+//|| helper: a fake backend whose calls are scripted per test (unused methods panic)
+//||------------------------------------------------------------------------------------------------||
+
+type fakeService struct {
+	StoreService
+	calls     int
+	get       func(ctx context.Context) ([]byte, error)
+	putStream func(ctx context.Context, r io.Reader) error
+	getStream func(ctx context.Context) (io.ReadCloser, error)
+}
+
+func (f *fakeService) Get(ctx context.Context, objectName string) ([]byte, error) {
+	f.calls++
+	return f.get(ctx)
+}
+
+func (f *fakeService) PutStream(ctx context.Context, objectName string, r io.Reader, size int64, opts PutOptions) error {
+	f.calls++
+	return f.putStream(ctx, r)
+}
+
+func (f *fakeService) GetStream(ctx context.Context, objectName string) (io.ReadCloser, ObjectInfo, error) {
+	f.calls++
+	rc, err := f.getStream(ctx)
+	return rc, ObjectInfo{Name: objectName}, err
+}
+
+func newFake(cfg StoreConfig, f *fakeService) *Storage {
+	return &Storage{Config: cfg, service: f}
+}
+
+// readAll reads r until EOF, giving up with ctx's error between reads as an SDK would.
+func readAll(ctx context.Context, r io.Reader) ([]byte, error) {
+	var out []byte
+	buf := make([]byte, 4)
+	for {
+		n, err := r.Read(buf)
+		out = append(out, buf[:n]...)
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return out, err
+		}
+		if ctx.Err() != nil {
+			return out, ctx.Err()
+		}
+	}
+}
+
+type slowReader struct {
+	r     io.Reader
+	delay time.Duration
+}
+
+func (s slowReader) Read(p []byte) (int, error) {
+	time.Sleep(s.delay)
+	return s.r.Read(p[:1])
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Retry: retryable errors are retried MaxRetries times, then returned
+//||------------------------------------------------------------------------------------------------||
+
+func TestRetry_Count(t *testing.T) {
+	cfg := StoreConfig{Retry: RetryPolicy{MaxRetries: 2, Backoff: time.Millisecond}}
+	ctx := context.Background()
+
+	fails := 2
+	f := &fakeService{get: func(context.Context) ([]byte, error) {
+		if fails > 0 {
+			fails--
+			return nil, &azcore.ResponseError{StatusCode: 503}
+		}
+		return []byte("ok"), nil
+	}}
+	if data, err := newFake(cfg, f).Get(ctx, "a"); err != nil || string(data) != "ok" || f.calls != 3 {
+		t.Fatalf("Get = %q, %v after %d calls; want ok after 3", data, err, f.calls)
+	}
+
+	f = &fakeService{get: func(context.Context) ([]byte, error) { return nil, io.ErrUnexpectedEOF }}
+	if _, err := newFake(cfg, f).Get(ctx, "a"); !errors.Is(err, io.ErrUnexpectedEOF) || f.calls != 3 {
+		t.Fatalf("Get = %v after %d calls; want io.ErrUnexpectedEOF after 3", err, f.calls)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Retry: not-found, 4xx and unknown errors fail on the first attempt
+//||------------------------------------------------------------------------------------------------||
+
+func TestRetry_NonRetryable(t *testing.T) {
+	cfg := StoreConfig{Retry: RetryPolicy{MaxRetries: 3, Backoff: time.Millisecond}}
+	for _, want := range []error{
+		notFound("a"),
+		&azcore.ResponseError{StatusCode: 403},
+		errors.New("bad request"),
+	} {
+		f := &fakeService{get: func(context.Context) ([]byte, error) { return nil, want }}
+		if _, err := newFake(cfg, f).Get(context.Background(), "a"); !errors.Is(err, want) || f.calls != 1 {
+			t.Errorf("Get = %v after %d calls; want %v after 1", err, f.calls, want)
+		}
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Timeout: calls are cut off; streams only when opening or when they stall
+//||------------------------------------------------------------------------------------------------||
+
+func TestTimeout(t *testing.T) {
+	cfg := StoreConfig{Timeout: 50 * time.Millisecond}
+	ctx := context.Background()
+
+	f := &fakeService{get: func(ctx context.Context) ([]byte, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}}
+	if _, err := newFake(cfg, f).Get(ctx, "a"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("blocked Get = %v, want DeadlineExceeded", err)
+	}
+
+	// 20 bytes at 10ms each outlast the timeout but never stall for it
+	var got []byte
+	f = &fakeService{putStream: func(ctx context.Context, r io.Reader) error {
+		var err error
+		got, err = readAll(ctx, r)
+		return err
+	}}
+	body := slowReader{strings.NewReader(strings.Repeat("x", 20)), 10 * time.Millisecond}
+	if err := newFake(cfg, f).PutStream(ctx, "a", body, SizeUnknown, PutOptions{}); err != nil || len(got) != 20 {
+		t.Fatalf("slow PutStream = %v with %d bytes; want nil with 20", err, len(got))
+	}
+	stalled := slowReader{strings.NewReader("x"), 150 * time.Millisecond}
+	if err := newFake(cfg, f).PutStream(ctx, "a", stalled, SizeUnknown, PutOptions{}); !errors.Is(err, ErrStreamTimeout) {
+		t.Fatalf("stalled PutStream = %v, want ErrStreamTimeout", err)
+	}
+
+	f = &fakeService{getStream: func(ctx context.Context) (io.ReadCloser, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}}
+	if _, _, err := newFake(cfg, f).GetStream(ctx, "a"); !errors.Is(err, ErrStreamTimeout) {
+		t.Fatalf("blocked GetStream = %v, want ErrStreamTimeout", err)
+	}
+	var opened context.Context
+	f = &fakeService{getStream: func(ctx context.Context) (io.ReadCloser, error) {
+		opened = ctx
+		return io.NopCloser(strings.NewReader("body")), nil
+	}}
+	rc, _, err := newFake(cfg, f).GetStream(ctx, "a")
+	if err != nil {
+		t.Fatalf("GetStream: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if opened.Err() != nil {
+		t.Fatalf("open stream cancelled after the timeout: %v", opened.Err())
+	}
+	rc.Close()
+	if opened.Err() == nil {
+		t.Fatal("stream context still live after Close")
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test PutStream: a seekable body is rewound to where it started before each retry
+//||------------------------------------------------------------------------------------------------||
+
+func TestPutStream_RewindsSeeker(t *testing.T) {
+	cfg := StoreConfig{Retry: RetryPolicy{MaxRetries: 1, Backoff: time.Millisecond}}
+	var attempts []string
+	f := &fakeService{putStream: func(ctx context.Context, r io.Reader) error {
+		if len(attempts) == 0 {
+			buf := make([]byte, 3)
+			n, _ := r.Read(buf)
+			attempts = append(attempts, string(buf[:n]))
+			return io.ErrUnexpectedEOF
+		}
+		data, err := io.ReadAll(r)
+		attempts = append(attempts, string(data))
+		return err
+	}}
+	body := bytes.NewReader([]byte("skip:payload"))
+	body.Seek(5, io.SeekStart)
+	if err := newFake(cfg, f).PutStream(context.Background(), "a", body, 7, PutOptions{}); err != nil {
+		t.Fatalf("PutStream: %v", err)
+	}
+	if len(attempts) != 2 || attempts[0] != "pay" || attempts[1] != "payload" {
+		t.Fatalf("attempts read %q, want [pay payload]", attempts)
+	}
+
+	f = &fakeService{putStream: func(context.Context, io.Reader) error { return io.ErrUnexpectedEOF }}
+	if err := newFake(cfg, f).PutStream(context.Background(), "a", io.MultiReader(body), 7, PutOptions{}); err == nil || f.calls != 1 {
+		t.Fatalf("non-seekable PutStream = %v after %d calls; want an error after 1", err, f.calls)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Local: every read of a missing key reports ErrNotFound
+//||------------------------------------------------------------------------------------------------||
+
+func TestLocal_NotFound(t *testing.T) {
+	st, _ := newLocal(t)
+	ctx := context.Background()
+	if _, err := st.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get = %v, want ErrNotFound", err)
+	}
+	if _, _, err := st.GetStream(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetStream = %v, want ErrNotFound", err)
+	}
+	if _, err := st.GetRange(ctx, "missing", 0, ToEnd); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetRange = %v, want ErrNotFound", err)
+	}
+	if err := st.Delete(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete = %v, want ErrNotFound", err)
+	}
+	if err := st.Move(ctx, "missing", "b"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Move = %v, want ErrNotFound", err)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Local: a sidecar left from before an interrupted write is not applied to the new data
+//||------------------------------------------------------------------------------------------------||
+
+func TestLocal_StaleSidecar(t *testing.T) {
+	st, dir := newLocal(t)
+	ctx := context.Background()
+	opts := PutOptions{ContentType: "application/json", Metadata: map[string]string{"v": "1"}}
+	if err := st.Put(ctx, "a.txt", []byte("v1"), opts); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	// the data rename landed, the sidecar rename did not
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("version 2"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	info, err := st.Stat(ctx, "a.txt")
+	if err != nil || info.ContentType != "text/plain; charset=utf-8" || info.Metadata != nil {
+		t.Fatalf("Stat = %+v, %v; want the extension's type and no metadata", info, err)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Local: Close releases the root
+//||------------------------------------------------------------------------------------------------||
+
+func TestLocal_Close(t *testing.T) {
+	st, _ := newLocal(t)
+	if err := st.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := st.Ping(); err == nil {
+		t.Fatal("Ping after Close succeeded")
+	}
+	if err := (&Storage{}).Close(); err != nil {
+		t.Fatalf("Close on an uninitialized storage: %v", err)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Local: PutStream/GetStream round trip, with and without a known size
+//||------------------------------------------------------------------------------------------------||
+
+func TestLocal_StreamRoundTrip(t *testing.T) {
+	st, _ := newLocal(t)
+	ctx := context.Background()
+	body := strings.Repeat("0123456789", 10000)
+
+	if err := st.PutStream(ctx, "big.bin", io.MultiReader(strings.NewReader(body)), SizeUnknown, PutOptions{}); err != nil {
+		t.Fatalf("PutStream(SizeUnknown): %v", err)
+	}
+	rc, info, err := st.GetStream(ctx, "big.bin")
+	if err != nil {
+		t.Fatalf("GetStream: %v", err)
+	}
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil || string(data) != body || info.Size != int64(len(body)) {
+		t.Fatalf("GetStream read %d bytes (size %d), %v; want %d", len(data), info.Size, err, len(body))
+	}
+
+	if err := st.PutStream(ctx, "big.bin", strings.NewReader("short"), 10, PutOptions{}); err == nil {
+		t.Fatal("PutStream with fewer bytes than size succeeded")
+	}
+	if data, _ := st.Get(ctx, "big.bin"); string(data) != body {
+		t.Fatal("a short PutStream replaced the object")
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Local: GetRange from the start, the middle and the end of an object
+//||------------------------------------------------------------------------------------------------||
+
+func TestLocal_GetRange(t *testing.T) {
+	st, _ := newLocal(t)
+	ctx := context.Background()
+	if err := st.Put(ctx, "r.txt", []byte("0123456789")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	cases := []struct {
+		offset, length int64
+		want           string
+	}{
+		{0, 3, "012"},
+		{0, ToEnd, "0123456789"},
+		{4, 3, "456"},
+		{4, ToEnd, "456789"},
+		{8, 5, "89"}, // runs past the end
+		{10, ToEnd, ""},
+		{10, 1, ""},
+	}
+	for _, c := range cases {
+		rc, err := st.GetRange(ctx, "r.txt", c.offset, c.length)
+		if err != nil {
+			t.Errorf("GetRange(%d, %d): %v", c.offset, c.length, err)
+			continue
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil || string(data) != c.want {
+			t.Errorf("GetRange(%d, %d) = %q, %v; want %q", c.offset, c.length, data, err, c.want)
+		}
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test checkRange: negative offsets and empty or negative lengths (other than ToEnd)
+//||------------------------------------------------------------------------------------------------||
+
+func TestCheckRange(t *testing.T) {
+	st, _ := newLocal(t)
+	for _, r := range [][2]int64{{-1, 1}, {-1, ToEnd}, {0, 0}, {5, -2}} {
+		if err := checkRange(r[0], r[1]); err == nil {
+			t.Errorf("checkRange(%d, %d) accepted", r[0], r[1])
+		}
+		if _, err := st.GetRange(context.Background(), "r.txt", r[0], r[1]); err == nil {
+			t.Errorf("GetRange(%d, %d) accepted", r[0], r[1])
+		}
+	}
+	for _, r := range [][2]int64{{0, 1}, {0, ToEnd}, {7, 100}} {
+		if err := checkRange(r[0], r[1]); err != nil {
+			t.Errorf("checkRange(%d, %d) = %v", r[0], r[1], err)
+		}
+	}
+	if got := httpRange(4, 3); got != "bytes=4-6" {
+		t.Errorf("httpRange(4, 3) = %q", got)
+	}
+	if got := httpRange(4, ToEnd); got != "bytes=4-" {
+		t.Errorf("httpRange(4, ToEnd) = %q", got)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Local: Stat returns what Put was given; Exists on a missing key
+//||------------------------------------------------------------------------------------------------||
+
+func TestLocal_StatMetadata(t *testing.T) {
+	st, _ := newLocal(t)
+	ctx := context.Background()
+	opts := PutOptions{
+		ContentType:  "application/x-custom",
+		CacheControl: "public, max-age=60",
+		Metadata:     map[string]string{"owner": "42", "origin": "upload"},
+	}
+	if err := st.Put(ctx, "m/obj.bin", []byte("hello"), opts); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	info, err := st.Stat(ctx, "m/obj.bin")
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.Name != "m/obj.bin" || info.Size != 5 || info.ContentType != opts.ContentType ||
+		info.CacheControl != opts.CacheControl || len(info.Metadata) != 2 || info.Metadata["owner"] != "42" {
+		t.Fatalf("Stat = %+v; want the options given to Put", info)
+	}
+
+	if err := st.Put(ctx, "m/obj.bin", []byte("hello")); err != nil {
+		t.Fatalf("Put without options: %v", err)
+	}
+	if info, _ := st.Stat(ctx, "m/obj.bin"); info.ContentType != "application/octet-stream" || info.Metadata != nil {
+		t.Fatalf("Stat after overwrite = %+v; want the old options gone", info)
+	}
+
+	if ok, err := st.Exists(ctx, "m/missing.bin"); ok || err != nil {
+		t.Fatalf("Exists(missing) = %v, %v; want false, nil", ok, err)
+	}
+	if ok, err := st.Exists(ctx, "m/obj.bin"); !ok || err != nil {
+		t.Fatalf("Exists(m/obj.bin) = %v, %v; want true, nil", ok, err)
+	}
+	if _, err := st.Stat(ctx, "m"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Stat(directory) = %v, want ErrNotFound", err)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Local: List pages through more than ListPageSize objects with the cursor
+//||------------------------------------------------------------------------------------------------||
+
+func TestLocal_ListPages(t *testing.T) {
+	st, dir := newLocal(t)
+	ctx := context.Background()
+	total := ListPageSize + 5
+	for _, sub := range []string{"p", "q"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < total; i++ {
+		if err := os.WriteFile(filepath.Join(dir, "p", fmt.Sprintf("%05d", i)), nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "q", "other"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	cursor, pages := "", 0
+	for {
+		page, err := st.List(ctx, "p/", cursor)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		pages++
+		for _, o := range page.Objects {
+			names = append(names, o.Name)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	if pages != 2 || len(names) != total {
+		t.Fatalf("List returned %d objects in %d pages; want %d in 2", len(names), pages, total)
+	}
+	for i, name := range names {
+		if want := fmt.Sprintf("p/%05d", i); name != want {
+			t.Fatalf("object %d = %q, want %q (sorted, no repeats)", i, name, want)
+		}
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Local: Copy keeps the source and its metadata; Move removes the source
+//||------------------------------------------------------------------------------------------------||
+
+func TestLocal_CopyMove(t *testing.T) {
+	st, _ := newLocal(t)
+	ctx := context.Background()
+	opts := PutOptions{ContentType: "text/markdown", Metadata: map[string]string{"k": "v"}}
+	if err := st.Put(ctx, "src.md", []byte("# doc"), opts); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	if err := st.Copy(ctx, "src.md", "copies/dst.md"); err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+	for _, key := range []string{"src.md", "copies/dst.md"} {
+		info, err := st.Stat(ctx, key)
+		if err != nil || info.ContentType != "text/markdown" || info.Metadata["k"] != "v" {
+			t.Fatalf("Stat(%s) after Copy = %+v, %v; want the source's metadata", key, info, err)
+		}
+	}
+
+	if err := st.Move(ctx, "src.md", "moved/final.md"); err != nil {
+		t.Fatalf("Move: %v", err)
+	}
+	if ok, _ := st.Exists(ctx, "src.md"); ok {
+		t.Fatal("source still exists after Move")
+	}
+	info, err := st.Stat(ctx, "moved/final.md")
+	if err != nil || info.ContentType != "text/markdown" || info.Metadata["k"] != "v" {
+		t.Fatalf("Stat after Move = %+v, %v; want the source's metadata", info, err)
+	}
+	if data, _ := st.Get(ctx, "moved/final.md"); string(data) != "# doc" {
+		t.Fatalf("moved object = %q", data)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| helper: a local storage that signs URLs for a handler mounted at /files
+//||------------------------------------------------------------------------------------------------||
+
+func newPresignLocal(t *testing.T) (*Storage, http.Handler) {
+	t.Helper()
+	st := &Storage{Config: StoreConfig{
+		Backend:   StorageLocal,
+		LocalPath: filepath.Join(t.TempDir(), "root"),
+		PublicURL: "http://files.test/files",
+		SignKey:   "test-sign-key",
+	}}
+	if err := st.InitStorage(); err != nil {
+		t.Fatalf("InitStorage: %v", err)
+	}
+	h, err := st.Handler()
+	if err != nil {
+		t.Fatalf("Handler: %v", err)
+	}
+	return st, http.StripPrefix("/files", h)
+}
+
+func serve(h http.Handler, method, url string, body io.Reader, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, url, body)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Local Handler: GET/HEAD on a download URL; expired, tampered and missing objects
+//||------------------------------------------------------------------------------------------------||
+
+func TestLocalHandler_Get(t *testing.T) {
+	st, h := newPresignLocal(t)
+	ctx := context.Background()
+	opts := PutOptions{ContentType: "text/markdown", CacheControl: "max-age=60"}
+	if err := st.Put(ctx, "docs/a b.md", []byte("# hello"), opts); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	url, err := st.PresignGet(ctx, "docs/a b.md", time.Minute)
+	if err != nil {
+		t.Fatalf("PresignGet: %v", err)
+	}
+
+	rec := serve(h, http.MethodGet, url, nil, nil)
+	if rec.Code != http.StatusOK || rec.Body.String() != "# hello" ||
+		rec.Header().Get("Content-Type") != "text/markdown" || rec.Header().Get("Cache-Control") != "max-age=60" {
+		t.Fatalf("GET = %d %q %v", rec.Code, rec.Body, rec.Header())
+	}
+	rec = serve(h, http.MethodHead, url, nil, nil)
+	if rec.Code != http.StatusOK || rec.Body.Len() != 0 || rec.Header().Get("Content-Length") != "7" {
+		t.Fatalf("HEAD = %d %q %v", rec.Code, rec.Body, rec.Header())
+	}
+	rec = serve(h, http.MethodGet, url, nil, map[string]string{"Range": "bytes=2-"})
+	if rec.Code != http.StatusPartialContent || rec.Body.String() != "hello" {
+		t.Fatalf("ranged GET = %d %q", rec.Code, rec.Body)
+	}
+
+	local := st.service.(*StorageEngineLocal)
+	expired, err := local.presign(http.MethodGet, "docs/a b.md", -time.Minute, PresignConstraints{})
+	if err != nil {
+		t.Fatalf("presign: %v", err)
+	}
+	if rec := serve(h, http.MethodGet, expired, nil, nil); rec.Code != http.StatusForbidden {
+		t.Fatalf("expired GET = %d, want 403", rec.Code)
+	}
+	i := strings.Index(url, "sig=") + len("sig=")
+	flipped := "0"
+	if url[i] == '0' {
+		flipped = "1"
+	}
+	if rec := serve(h, http.MethodGet, url[:i]+flipped+url[i+1:], nil, nil); rec.Code != http.StatusForbidden {
+		t.Fatalf("tampered signature GET = %d, want 403", rec.Code)
+	}
+	other := strings.Replace(url, "a%20b.md", "other.md", 1)
+	if rec := serve(h, http.MethodGet, other, nil, nil); rec.Code != http.StatusForbidden {
+		t.Fatalf("GET of another key with the same signature = %d, want 403", rec.Code)
+	}
+
+	missing, _ := st.PresignGet(ctx, "docs/missing.md", time.Minute)
+	if rec := serve(h, http.MethodGet, missing, nil, nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("GET missing = %d, want 404", rec.Code)
+	}
+	st.Close()
+	if rec := serve(h, http.MethodGet, url, nil, nil); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("GET after the root closed = %d, want 500", rec.Code)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Local Handler: PUT honours the method and the signed Content-Type/Content-Length
+//||------------------------------------------------------------------------------------------------||
+
+func TestLocalHandler_Put(t *testing.T) {
+	st, h := newPresignLocal(t)
+	ctx := context.Background()
+
+	getURL, _ := st.PresignGet(ctx, "up.json", time.Minute)
+	if rec := serve(h, http.MethodPut, getURL, strings.NewReader("{}"), nil); rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("PUT on a GET URL = %d, want 405", rec.Code)
+	}
+
+	url, err := st.PresignPut(ctx, "up.json", time.Minute, PresignConstraints{ContentType: "application/json", ContentLength: 2})
+	if err != nil {
+		t.Fatalf("PresignPut: %v", err)
+	}
+	if rec := serve(h, http.MethodPut, url, strings.NewReader("{}"), map[string]string{"Content-Type": "text/plain"}); rec.Code != http.StatusBadRequest {
+		t.Fatalf("PUT with the wrong Content-Type = %d, want 400", rec.Code)
+	}
+	jsonType := map[string]string{"Content-Type": "application/json"}
+	if rec := serve(h, http.MethodPut, url, strings.NewReader("{ }"), jsonType); rec.Code != http.StatusBadRequest {
+		t.Fatalf("PUT with the wrong Content-Length = %d, want 400", rec.Code)
+	}
+	req := httptest.NewRequest(http.MethodPut, url, strings.NewReader(`{"a":1}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.ContentLength = 2 // claims the signed size, sends more
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("PUT with a body past Content-Length = %d, want 413", rec.Code)
+	}
+	if ok, _ := st.Exists(ctx, "up.json"); ok {
+		t.Fatal("rejected PUTs stored an object")
+	}
+
+	if rec := serve(h, http.MethodPut, url, strings.NewReader("{}"), jsonType); rec.Code != http.StatusOK {
+		t.Fatalf("PUT = %d %q, want 200", rec.Code, rec.Body)
+	}
+	info, err := st.Stat(ctx, "up.json")
+	if err != nil || info.Size != 2 || info.ContentType != "application/json" {
+		t.Fatalf("Stat after PUT = %+v, %v", info, err)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| helper: a GCS engine talking to a fake JSON API that records the uploads it completes
+//||------------------------------------------------------------------------------------------------||
+
+func newFakeGCS(t *testing.T) (*StorageEngineGCP, *[]string) {
+	t.Helper()
+	var mu sync.Mutex
+	var committed []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil || r.Method != http.MethodPost {
+			return // aborted upload: the client went away mid-body
+		}
+		mu.Lock()
+		committed = append(committed, string(body))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"bucket":"b","name":"obj"}`)
+	}))
+	t.Cleanup(srv.Close)
+	client, err := gcs.NewClient(context.Background(), option.WithEndpoint(srv.URL+"/storage/v1/"), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("gcs.NewClient: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return &StorageEngineGCP{client: client, bucket: "b"}, &committed
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test GCP PutStream: a failing or short reader aborts the upload instead of committing it
+//||------------------------------------------------------------------------------------------------||
+
+func TestGCP_PutStreamAborts(t *testing.T) {
+	g, committed := newFakeGCS(t)
+	ctx := context.Background()
+
+	failing := io.MultiReader(strings.NewReader("partial"), errReader{})
+	if err := g.PutStream(ctx, "obj", failing, SizeUnknown, PutOptions{}); err == nil || !strings.Contains(err.Error(), "connection lost") {
+		t.Fatalf("PutStream with a failing reader = %v, want the reader's error", err)
+	}
+	if err := g.PutStream(ctx, "obj", strings.NewReader("short"), 10, PutOptions{}); err == nil || !strings.Contains(err.Error(), "short write") {
+		t.Fatalf("PutStream with fewer bytes than size = %v, want a short write error", err)
+	}
+	if len(*committed) != 0 {
+		t.Fatalf("failed uploads committed %d objects", len(*committed))
+	}
+
+	if err := g.PutStream(ctx, "obj", strings.NewReader("complete"), 8, PutOptions{}); err != nil {
+		t.Fatalf("PutStream: %v", err)
+	}
+	if len(*committed) != 1 || !strings.Contains((*committed)[0], "complete") {
+		t.Fatalf("committed = %q, want one upload carrying the body", *committed)
+	}
+}
+
+//||------------------------------------------------------------------------------------------------||
+//|| Test Cloud Read Paths: a missing object is ErrNotFound from Get, GetStream, GetRange and Stat
+//||------------------------------------------------------------------------------------------------||
+
+func TestCloud_ReadNotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.Header().Set("x-ms-error-code", "BlobNotFound")
+		w.WriteHeader(http.StatusNotFound)
+		if r.Method != http.MethodHead {
+			io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>missing</Message></Error>`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	ctx := context.Background()
+
+	s3Client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(srv.URL),
+		UsePathStyle: true,
+		Credentials:  aws.AnonymousCredentials{},
+	})
+	minioClient, err := minio.New(strings.TrimPrefix(srv.URL, "http://"), &minio.Options{
+		Creds:  miniocreds.NewStaticV4("key", "secret", ""),
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("minio.New: %v", err)
+	}
+	gcsClient, err := gcs.NewClient(ctx, option.WithEndpoint(srv.URL+"/storage/v1/"), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("gcs.NewClient: %v", err)
+	}
+	t.Cleanup(func() { gcsClient.Close() })
+	azureClient, err := container.NewClientWithNoCredential(srv.URL+"/c", nil)
+	if err != nil {
+		t.Fatalf("container.NewClientWithNoCredential: %v", err)
+	}
+
+	backends := map[string]StoreService{
+		"s3":    &StorageEngineS3{client: s3Client, config: StoreConfig{Bucket: "bucket"}},
+		"minio": &StorageEngineMinio{client: minioClient, config: StoreConfig{Bucket: "bucket"}},
+		"gcp":   &StorageEngineGCP{client: gcsClient, bucket: "b"},
+		"azure": &StorageEngineAzure{containerClient: azureClient},
+	}
+	for name, b := range backends {
+		if _, err := b.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("%s Get = %v, want ErrNotFound", name, err)
+		}
+		if _, _, err := b.GetStream(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("%s GetStream = %v, want ErrNotFound", name, err)
+		}
+		if _, err := b.GetRange(ctx, "missing", 2, 4); !errors.Is(err, ErrNotFound) {
+			t.Errorf("%s GetRange = %v, want ErrNotFound", name, err)
+		}
+		if _, err := b.Stat(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("%s Stat = %v, want ErrNotFound", name, err)
+		}
+	}
+}